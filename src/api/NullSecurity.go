@@ -50,3 +50,13 @@ func (sec *NullSecurity) SetUsername(v contract.ParameterAccess) {
 func (sec *NullSecurity) SetPassword(v contract.ParameterAccess) {
 	sec.Report()
 }
+
+// SetClientID reports an error.
+func (sec *NullSecurity) SetClientID(v contract.ParameterAccess) {
+	sec.Report()
+}
+
+// SetClientSecret reports an error.
+func (sec *NullSecurity) SetClientSecret(v contract.ParameterAccess) {
+	sec.Report()
+}
@@ -0,0 +1,87 @@
+package openapi3
+
+import (
+	"math"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/test/har"
+)
+
+// Conformance checks the recorded HTTP exchanges against the spec
+// without replaying them. Exchanges are matched to the spec operations
+// by method & path template, their responses are validated the same way
+// as in tests: status, content type, headers and body schema.
+func Conformance(spec *Spec, entries []har.Entry, log contract.Logger) contract.ConformanceReport {
+	report := contract.ConformanceReport{
+		Spec:    spec.Path,
+		Title:   spec.Title(),
+		Entries: []contract.ConformanceEntry{},
+	}
+
+	for _, entry := range entries {
+		ce := EntryConformance(spec, entry, log)
+		report.Entries = append(report.Entries, ce)
+
+		if ce.Aborted {
+			report.Aborted++
+			continue
+		}
+
+		report.Total++
+		if ce.Conformant {
+			report.Conformant++
+		}
+	}
+
+	if report.Total > 0 {
+		report.Percent = math.Round(1000*float64(report.Conformant)/float64(report.Total)) / 10
+	}
+
+	return report
+}
+
+// EntryConformance checks a single recorded exchange against the spec.
+// Exchanges with the status 0 never got a response, so they are marked
+// as aborted instead.
+func EntryConformance(spec *Spec, entry har.Entry, log contract.Logger) contract.ConformanceEntry {
+	ce := contract.ConformanceEntry{
+		Method: entry.Request.Method,
+		URL:    entry.Request.URL,
+		Status: entry.Response.Status,
+	}
+
+	if ce.Status == 0 {
+		ce.Aborted = true
+		return ce
+	}
+
+	done := func(violations ...contract.Violation) contract.ConformanceEntry {
+		ce.Violations = violations
+		ce.Conformant = len(violations) == 0
+		return ce
+	}
+
+	req, reqBody, err := entry.HTTPRequest()
+	if err != nil {
+		return done(contract.Violation{Location: "request", Message: err.Error()})
+	}
+
+	specPath, _ := spec.MatchPath(req.URL.Path)
+	if specPath == "" {
+		return done(contract.Violation{Message: "No spec path matches " + req.URL.Path + "."})
+	}
+
+	op := spec.PathOperation(req.Method, specPath)
+	if op == nil {
+		return done(contract.Violation{Message: "The " + specPath + " path has no " + req.Method + " operation."})
+	}
+
+	ce.Operation = OperationName(op)
+
+	resp, respBody, err := entry.HTTPResponse(req)
+	if err != nil {
+		return done(contract.Violation{Location: "response body", Message: err.Error()})
+	}
+
+	return done(ValidateResponse(op, req, reqBody, resp, respBody, log)...)
+}
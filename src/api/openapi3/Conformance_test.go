@@ -0,0 +1,87 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/har"
+)
+
+func Test_Conformance(T *testing.T) {
+	spec, err := openapi3.Load("../../../spec/test/pets.yaml", log.NewPlain(0))
+	assert.Nil(T, err)
+
+	recorded, err := har.Load("../../../spec/test/pets.har")
+	assert.Nil(T, err)
+
+	report := openapi3.Conformance(spec, recorded.Log.Entries, log.NewPlain(0))
+
+	expected := []contract.ConformanceEntry{
+		{
+			Method:     "GET",
+			URL:        "http://localhost/v1/pets/1",
+			Status:     200,
+			Operation:  "GET /pets/{id}",
+			Conformant: true,
+			Violations: []contract.Violation{},
+		},
+		{
+			Method:    "GET",
+			URL:       "http://localhost/v1/pets/2",
+			Status:    200,
+			Operation: "GET /pets/{id}",
+			Violations: []contract.Violation{
+				{Location: "Response kind", Message: `kind must be one of the following: "dog", "cat"`},
+				{Location: "Response name", Message: "String length must be greater than or equal to 2"},
+			},
+		},
+		{
+			Method:     "GET",
+			URL:        "http://localhost/v1/pets/3",
+			Status:     404,
+			Operation:  "GET /pets/{id}",
+			Conformant: true,
+			Violations: []contract.Violation{},
+		},
+		{
+			Method:    "POST",
+			URL:       "http://localhost/v1/pets",
+			Status:    201,
+			Operation: "POST /pets",
+			Violations: []contract.Violation{
+				{Location: "Location", Message: "Does not match format 'uri'"},
+			},
+		},
+		{
+			Method:    "GET",
+			URL:       "http://localhost/v1/pets/mine",
+			Status:    500,
+			Operation: "GET /pets/mine",
+			Violations: []contract.Violation{
+				{Location: "response", Message: "The status 500 is not documented."},
+			},
+		},
+		{
+			Method: "GET",
+			URL:    "http://localhost/v1/owners",
+			Status: 200,
+			Violations: []contract.Violation{
+				{Message: "No spec path matches /v1/owners."},
+			},
+		},
+		{
+			Method:  "GET",
+			URL:     "http://localhost/v1/pets/4",
+			Aborted: true,
+		},
+	}
+
+	assert.Equal(T, expected, report.Entries)
+	assert.Equal(T, 6, report.Total)
+	assert.Equal(T, 2, report.Conformant)
+	assert.Equal(T, 1, report.Aborted)
+	assert.Equal(T, 33.3, report.Percent)
+}
@@ -0,0 +1,444 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Coverage collects the parts of a spec exercised by tests: operations,
+// documented responses, parameters and schema branches (enum values
+// and oneOf/anyOf alternatives). Every part has a name, which is the same
+// for the spec and for the observed requests & responses, so the report
+// is simply a list of the spec parts with the observed ones marked.
+type Coverage struct {
+	Spec *Spec
+	Hits map[string]map[string]bool
+}
+
+// NewCoverage creates a new Coverage instance.
+func NewCoverage(spec *Spec) *Coverage {
+	return &Coverage{
+		Spec: spec,
+		Hits: map[string]map[string]bool{},
+	}
+}
+
+// OperationName is how operations are named in coverage reports, like "GET /pets/{id}".
+func OperationName(op *Operation) string {
+	return op.RequestMethod + " " + op.RequestPath
+}
+
+// EnumBranch is a name of an enum value branch.
+func EnumBranch(location string, v interface{}) string {
+	return fmt.Sprintf("%s enum %v", location, v)
+}
+
+// AlternativeBranch is a name of a oneOf or anyOf alternative branch.
+func AlternativeBranch(location string, kind string, i int) string {
+	return fmt.Sprintf("%s %s #%d", location, kind, i)
+}
+
+// Add records the parts of the operation exercised by the result.
+// Operations from other specs and results without requests are ignored.
+func (cov *Coverage) Add(op contract.Operation, result *contract.OperationResult) {
+	oasOp, ok := op.(*Operation)
+	if !ok || result == nil || result.HTTPRequest == nil {
+		return
+	}
+
+	specPath := cov.Spec.OAS.Paths[oasOp.RequestPath]
+	if specPath == nil || specPath.GetOperation(oasOp.RequestMethod) == nil {
+		return
+	}
+
+	name := OperationName(oasOp)
+	if cov.Hits[name] == nil {
+		cov.Hits[name] = map[string]bool{}
+	}
+
+	hits := cov.Hits[name]
+
+	cov.AddParameters(hits, oasOp, result)
+	cov.AddRequestBody(hits, oasOp, result)
+	cov.AddResponse(hits, oasOp, result)
+}
+
+// AddParameters records the sent parameters & their enum values.
+func (cov *Coverage) AddParameters(hits map[string]bool, op *Operation, result *contract.OperationResult) {
+	params, keys := ParameterMap(op)
+
+	for _, key := range keys {
+		p := params[key]
+		location := "parameter " + p.In + " " + p.Name
+
+		values := ParameterValues(p, result.HTTPRequest, result.PathParameters)
+		if len(values) > 0 {
+			hits[p.In+" "+p.Name] = true
+		}
+
+		for _, v := range values {
+			cov.Hit(hits, location, "", p.Schema, ParameterValue(v, p.Schema))
+		}
+	}
+}
+
+// AddRequestBody records the sent request body properties & schema branches.
+func (cov *Coverage) AddRequestBody(hits map[string]bool, op *Operation, result *contract.OperationResult) {
+	if op.SpecOp.RequestBody == nil || op.SpecOp.RequestBody.Value == nil {
+		return
+	}
+
+	CT := MediaType(result.HTTPRequest.Header.Get("Content-Type"))
+
+	mt := op.SpecOp.RequestBody.Value.Content[CT]
+	if mt == nil {
+		return
+	}
+
+	data := DecodeBody(CT, result.RequestBytes)
+
+	if obj, ok := data.(map[string]interface{}); ok {
+		for name := range obj {
+			hits["body "+name] = true
+		}
+	}
+
+	cov.Hit(hits, "request body "+CT, "", mt.Schema, data)
+}
+
+// AddResponse records the received response & it's schema branches.
+func (cov *Coverage) AddResponse(hits map[string]bool, op *Operation, result *contract.OperationResult) {
+	if result.HTTPResponse == nil {
+		return
+	}
+
+	status := ResponseKey(op.SpecOp.Responses, result.HTTPResponse.StatusCode)
+	if status == "" {
+		return
+	}
+
+	specResp := op.SpecOp.Responses[status].Value
+
+	if len(specResp.Content) == 0 {
+		hits[status] = true
+		return
+	}
+
+	CT := MediaType(result.HTTPResponse.Header.Get("Content-Type"))
+
+	mt := specResp.Content[CT]
+	if mt == nil {
+		return
+	}
+
+	hits[status+" "+CT] = true
+
+	cov.Hit(hits, "response "+status+" "+CT, "", mt.Schema, DecodeBody(CT, result.ResponseBytes))
+}
+
+// Hit records the schema branches taken by data.
+// path is a location within the schema, like ".owner.tags[]".
+func (cov *Coverage) Hit(hits map[string]bool, location string, path string, ref *openapi3.SchemaRef, data interface{}) {
+	if ref == nil || ref.Value == nil || data == nil {
+		return
+	}
+
+	s := ref.Value
+	loc := strings.TrimSpace(location + " " + path)
+
+	for _, v := range s.Enum {
+		if fmt.Sprint(v) == fmt.Sprint(data) {
+			hits[EnumBranch(loc, v)] = true
+		}
+	}
+
+	if obj, ok := data.(map[string]interface{}); ok {
+		for name, prop := range s.Properties {
+			if v, ok := obj[name]; ok {
+				cov.Hit(hits, location, path+"."+name, prop, v)
+			}
+		}
+	}
+
+	if arr, ok := data.([]interface{}); ok {
+		for _, item := range arr {
+			cov.Hit(hits, location, path+"[]", s.Items, item)
+		}
+	}
+
+	alternatives := func(kind string, alts []*openapi3.SchemaRef) {
+		for i, alt := range alts {
+			if alt != nil && alt.Value != nil && alt.Value.IsMatching(data) {
+				hits[AlternativeBranch(loc, kind, i)] = true
+				cov.Hit(hits, location, fmt.Sprintf("%s(%s #%d)", path, kind, i), alt, data)
+			}
+		}
+	}
+
+	alternatives("oneOf", s.OneOf)
+	alternatives("anyOf", s.AnyOf)
+
+	for _, sub := range s.AllOf {
+		cov.Hit(hits, location, path, sub, data)
+	}
+}
+
+// Report creates a coverage report for the entire spec.
+func (cov *Coverage) Report() contract.CoverageReport {
+	report := contract.CoverageReport{
+		Spec:       cov.Spec.Path,
+		Title:      cov.Spec.Title(),
+		Operations: []contract.OperationCoverage{},
+	}
+
+	for op := range cov.Spec.Operations() {
+		opc := cov.OperationReport(op.(*Operation))
+
+		for _, item := range opc.Items() {
+			report.Total++
+			if item.Covered {
+				report.Covered++
+			}
+		}
+
+		report.Operations = append(report.Operations, opc)
+	}
+
+	if report.Total > 0 {
+		report.Percent = math.Round(1000*float64(report.Covered)/float64(report.Total)) / 10
+	}
+
+	return report
+}
+
+// OperationReport lists the parts of an operation and tells which of them are covered.
+func (cov *Coverage) OperationReport(op *Operation) contract.OperationCoverage {
+	name := OperationName(op)
+	hits := cov.Hits[name]
+
+	items := func(names []string) []contract.CoverageItem {
+		res := []contract.CoverageItem{}
+		seen := map[string]bool{}
+
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				res = append(res, contract.CoverageItem{Name: n, Covered: hits[n]})
+			}
+		}
+
+		return res
+	}
+
+	responses := []string{}
+	parameters := []string{}
+	branches := []string{}
+
+	params, keys := ParameterMap(op)
+	for _, key := range keys {
+		p := params[key]
+		parameters = append(parameters, p.In+" "+p.Name)
+		branches = append(branches, Branches("parameter "+p.In+" "+p.Name, "", p.Schema, map[*openapi3.Schema]bool{})...)
+	}
+
+	if op.SpecOp.RequestBody != nil && op.SpecOp.RequestBody.Value != nil {
+		content := op.SpecOp.RequestBody.Value.Content
+
+		for _, CT := range SortedKeys(content) {
+			if content[CT].Schema == nil || content[CT].Schema.Value == nil {
+				continue
+			}
+
+			for _, prop := range Properties(content[CT].Schema.Value) {
+				parameters = append(parameters, "body "+prop)
+			}
+
+			branches = append(branches, Branches("request body "+CT, "", content[CT].Schema, map[*openapi3.Schema]bool{})...)
+		}
+	}
+
+	for _, status := range SortedKeys(op.SpecOp.Responses) {
+		specResp := op.SpecOp.Responses[status]
+		if specResp == nil || specResp.Value == nil {
+			continue
+		}
+
+		if len(specResp.Value.Content) == 0 {
+			responses = append(responses, status)
+			continue
+		}
+
+		for _, CT := range SortedKeys(specResp.Value.Content) {
+			responses = append(responses, status+" "+CT)
+			branches = append(branches, Branches("response "+status+" "+CT, "", specResp.Value.Content[CT].Schema, map[*openapi3.Schema]bool{})...)
+		}
+	}
+
+	return contract.OperationCoverage{
+		Operation:  name,
+		ID:         op.ID(),
+		Covered:    hits != nil,
+		Responses:  items(responses),
+		Parameters: items(parameters),
+		Branches:   items(branches),
+	}
+}
+
+// Branches lists the enum values & oneOf/anyOf alternatives of a schema.
+// Recursive schemas are walked until they recur.
+func Branches(location string, path string, ref *openapi3.SchemaRef, stack map[*openapi3.Schema]bool) []string {
+	if ref == nil || ref.Value == nil || stack[ref.Value] {
+		return []string{}
+	}
+
+	s := ref.Value
+	loc := strings.TrimSpace(location + " " + path)
+
+	stack[s] = true
+	defer delete(stack, s)
+
+	res := []string{}
+
+	for _, v := range s.Enum {
+		res = append(res, EnumBranch(loc, v))
+	}
+
+	for _, name := range SortedKeys(s.Properties) {
+		res = append(res, Branches(location, path+"."+name, s.Properties[name], stack)...)
+	}
+
+	res = append(res, Branches(location, path+"[]", s.Items, stack)...)
+
+	alternatives := func(kind string, alts []*openapi3.SchemaRef) {
+		for i, alt := range alts {
+			res = append(res, AlternativeBranch(loc, kind, i))
+			res = append(res, Branches(location, fmt.Sprintf("%s(%s #%d)", path, kind, i), alt, stack)...)
+		}
+	}
+
+	alternatives("oneOf", s.OneOf)
+	alternatives("anyOf", s.AnyOf)
+
+	for _, sub := range s.AllOf {
+		res = append(res, Branches(location, path, sub, stack)...)
+	}
+
+	return res
+}
+
+// Properties returns the top-level property names of an object schema, including the allOf ones.
+func Properties(s *openapi3.Schema) []string {
+	res := SortedKeys(s.Properties)
+
+	for _, sub := range s.AllOf {
+		if sub != nil && sub.Value != nil {
+			res = append(res, Properties(sub.Value)...)
+		}
+	}
+
+	return res
+}
+
+// ResponseKey finds the spec response key for an HTTP status,
+// which is either the status itself, a range like "2XX" or "default".
+func ResponseKey(resps openapi3.Responses, status int) string {
+	sstatus := strconv.Itoa(status)
+
+	for _, key := range []string{sstatus, sstatus[:1] + "XX", "default"} {
+		if resps[key] != nil && resps[key].Value != nil {
+			return key
+		}
+	}
+
+	return ""
+}
+
+// ParameterValues returns the values of the parameter p sent with the request.
+func ParameterValues(p *openapi3.Parameter, req *http.Request, pathParams map[string]string) []string {
+	switch p.In {
+	case "path":
+		if v, ok := pathParams[p.Name]; ok && v != "" {
+			return []string{v}
+		}
+
+	case "query":
+		return req.URL.Query()[p.Name]
+
+	case "header":
+		return req.Header[http.CanonicalHeaderKey(p.Name)]
+
+	case "cookie":
+		if c, err := req.Cookie(p.Name); err == nil {
+			return []string{c.Value}
+		}
+	}
+
+	return []string{}
+}
+
+// ParameterValue converts a parameter string to a JSON value of the schema type,
+// so it can be matched against schemas. Array items are comma-separated.
+func ParameterValue(v string, ref *openapi3.SchemaRef) interface{} {
+	if ref == nil || ref.Value == nil || ref.Value.Type == "string" {
+		return v
+	}
+
+	if ref.Value.Type == "array" {
+		res := []interface{}{}
+		for _, item := range strings.Split(v, ",") {
+			res = append(res, ParameterValue(item, ref.Value.Items))
+		}
+
+		return res
+	}
+
+	var res interface{}
+	if json.Unmarshal([]byte(v), &res) != nil {
+		return v
+	}
+
+	return res
+}
+
+// MediaType returns the media type of a Content-Type header value without it's parameters.
+func MediaType(CT string) string {
+	mt, _, err := mime.ParseMediaType(CT)
+	if err != nil {
+		return CT
+	}
+
+	return mt
+}
+
+// DecodeBody decodes JSON & form bodies, the other ones are ignored.
+func DecodeBody(CT string, body []byte) interface{} {
+	if CT == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+
+		res := map[string]interface{}{}
+		for k := range form {
+			res[k] = form.Get(k)
+		}
+
+		return res
+	}
+
+	var res interface{}
+	if json.Unmarshal(body, &res) != nil {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,82 @@
+package openapi3_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Coverage(T *testing.T) {
+	path := "../../../spec/test/coverage.yaml"
+
+	spec, err := openapi3.Load(path, log.NewPlain(0))
+	assert.Nil(T, err)
+
+	req, _ := http.NewRequest("GET", "http://localhost/pets/1?view=full", nil)
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:       ioutil.NopCloser(&bytes.Buffer{}),
+	}
+
+	cov := openapi3.NewCoverage(spec)
+	cov.Add(spec.GetOperation("getPet"), &contract.OperationResult{
+		HTTPRequest:    req,
+		HTTPResponse:   resp,
+		ResponseBytes:  []byte(`{"status": "sold", "owner": {"name": "Rex"}}`),
+		PathParameters: map[string]string{"id": "1"},
+	})
+
+	// Not requested, so ignored.
+	cov.Add(spec.GetOperation("listStores"), &contract.OperationResult{})
+
+	item := func(name string, covered bool) contract.CoverageItem {
+		return contract.CoverageItem{Name: name, Covered: covered}
+	}
+
+	report := cov.Report()
+
+	assert.Equal(T, path, report.Spec)
+	assert.Equal(T, "Pets", report.Title)
+	assert.Equal(T, 7, report.Covered)
+	assert.Equal(T, 13, report.Total)
+	assert.Equal(T, 53.8, report.Percent)
+
+	assert.Equal(T, []contract.OperationCoverage{
+		{
+			Operation: "GET /pets/{id}",
+			ID:        "getPet",
+			Covered:   true,
+			Responses: []contract.CoverageItem{
+				item("200 application/json", true),
+				item("404", false),
+			},
+			Parameters: []contract.CoverageItem{
+				item("path id", true),
+				item("query view", true),
+			},
+			Branches: []contract.CoverageItem{
+				item("parameter query view enum short", false),
+				item("parameter query view enum full", true),
+				item("response 200 application/json .owner oneOf #0", false),
+				item("response 200 application/json .owner oneOf #1", true),
+				item("response 200 application/json .status enum available", false),
+				item("response 200 application/json .status enum sold", true),
+			},
+		},
+		{
+			Operation:  "GET /stores",
+			ID:         "listStores",
+			Covered:    false,
+			Responses:  []contract.CoverageItem{item("200", false)},
+			Parameters: []contract.CoverageItem{},
+			Branches:   []contract.CoverageItem{},
+		},
+	}, report.Operations)
+}
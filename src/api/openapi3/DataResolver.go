@@ -16,6 +16,8 @@ import (
 
 	secAPIKey "github.com/x1n13y84issmd42/oasis/src/api/security/APIKey"
 	secHTTP "github.com/x1n13y84issmd42/oasis/src/api/security/HTTP"
+	secOAuth2 "github.com/x1n13y84issmd42/oasis/src/api/security/OAuth2"
+	secOpenIDConnect "github.com/x1n13y84issmd42/oasis/src/api/security/OpenIDConnect"
 )
 
 // DataResolver provides spec data based on user input.
@@ -27,6 +29,10 @@ type DataResolver struct {
 	Spec          *openapi3.Swagger
 	Op            *Operation
 	SpecResponses *openapi3.Responses
+
+	// SpecFile is the path of the spec file, local files referenced
+	// by the spec are relative to it.
+	SpecFile string
 }
 
 // ResolverExpectedHeader is header to expect.
@@ -103,6 +109,40 @@ func (resolver *DataResolver) SecurityName(name string) string {
 	return result
 }
 
+// SecurityScopes returns the scopes the operation requires from the security scheme.
+func (resolver *DataResolver) SecurityScopes(secName string) []string {
+	secReqs := resolver.Op.SpecOp.Security
+	if secReqs == nil {
+		secReqs = &resolver.Spec.Security
+	}
+
+	for _, secReq := range *secReqs {
+		if scopes, ok := secReq[secName]; ok {
+			return scopes
+		}
+	}
+
+	return []string{}
+}
+
+// SecurityExtension returns a string value of a security scheme extension, like "x-token".
+func (resolver *DataResolver) SecurityExtension(scheme *openapi3.SecurityScheme, n string) (string, error) {
+	if scheme.Extensions[n] != nil {
+		ev := scheme.Extensions[n]
+		if jre, ok := ev.(json.RawMessage); ok {
+			v := ""
+			err := json.Unmarshal(jre, &v)
+			if err != nil {
+				return "", errors.Oops("Cannot unmarshal the '"+n+"' field.", err)
+			}
+
+			return v, nil
+		}
+	}
+
+	return "", nil
+}
+
 // SecurityCredentials returns a username, password and token when available.
 func (resolver *DataResolver) SecurityCredentials(scheme *openapi3.SecurityScheme) (string, string, string, error) {
 	username := ""
@@ -110,20 +150,7 @@ func (resolver *DataResolver) SecurityCredentials(scheme *openapi3.SecuritySchem
 	token := ""
 
 	extract := func(n string) (string, error) {
-		if scheme.Extensions[n] != nil {
-			ev := scheme.Extensions[n]
-			if jre, ok := ev.(json.RawMessage); ok {
-				v := ""
-				err := json.Unmarshal(jre, &v)
-				if err != nil {
-					return "", errors.Oops("Cannot unmarshal the '"+n+"' field.", err)
-				}
-
-				return v, nil
-			}
-		}
-
-		return "", nil
+		return resolver.SecurityExtension(scheme, n)
 	}
 
 	username, err := extract("x-username")
@@ -173,6 +200,20 @@ func (resolver *DataResolver) Security(name string) contract.Security {
 
 			case "http":
 				return secHTTP.New(secName, secScheme.Scheme, token, username, password, resolver.Log)
+
+			case "oauth2":
+				return resolver.OAuth2(secName, secScheme, secOAuth2.Credentials{
+					Token:    token,
+					Username: username,
+					Password: password,
+				})
+
+			case "openIdConnect":
+				return resolver.OpenIDConnect(secName, secScheme, secOAuth2.Credentials{
+					Token:    token,
+					Username: username,
+					Password: password,
+				})
 			}
 		}
 	}
@@ -180,6 +221,81 @@ func (resolver *DataResolver) Security(name string) contract.Security {
 	return security.Insecurity(resolver.Log)
 }
 
+// ClientCredentials adds the client ID & secret and a refresh token
+// from the "x-client-id", "x-client-secret" & "x-refresh-token" extensions to creds.
+func (resolver *DataResolver) ClientCredentials(scheme *openapi3.SecurityScheme, creds *secOAuth2.Credentials) error {
+	var err error
+
+	for n, v := range map[string]*string{
+		"x-client-id":     &creds.ClientID,
+		"x-client-secret": &creds.ClientSecret,
+		"x-refresh-token": &creds.RefreshToken,
+	} {
+		*v, err = resolver.SecurityExtension(scheme, n)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OAuth2 creates an OAuth2 security from the flows of the scheme.
+func (resolver *DataResolver) OAuth2(secName string, scheme *openapi3.SecurityScheme, creds secOAuth2.Credentials) contract.Security {
+	err := resolver.ClientCredentials(scheme, &creds)
+	if err != nil {
+		return api.NoSecurity(err, resolver.Log)
+	}
+
+	flows := secOAuth2.Flows{}
+
+	if specFlows := scheme.Flows; specFlows != nil {
+		if specFlows.Password != nil {
+			flows.Password = specFlows.Password.TokenURL
+		}
+
+		if specFlows.ClientCredentials != nil {
+			flows.ClientCredentials = specFlows.ClientCredentials.TokenURL
+		}
+
+		// Refresh tokens are exchanged at the first refresh or token URL found.
+		for _, flow := range []*openapi3.OAuthFlow{specFlows.AuthorizationCode, specFlows.Password, specFlows.ClientCredentials} {
+			if flow != nil && flows.Refresh == "" {
+				flows.Refresh = flow.RefreshURL
+				if flows.Refresh == "" {
+					flows.Refresh = flow.TokenURL
+				}
+			}
+		}
+	}
+
+	return secOAuth2.New(secName, flows, resolver.SecurityScopes(secName), creds, resolver.Log)
+}
+
+// OpenIDConnect creates an OpenID Connect security from the scheme.
+// The OAS "openIdConnectUrl" field may be a URL or a path to a local file
+// relative to the spec, which stands in for the actual provider configuration.
+func (resolver *DataResolver) OpenIDConnect(secName string, scheme *openapi3.SecurityScheme, creds secOAuth2.Credentials) contract.Security {
+	err := resolver.ClientCredentials(scheme, &creds)
+	if err != nil {
+		return api.NoSecurity(err, resolver.Log)
+	}
+
+	// The vendored kin-openapi has no field for it, so it ends up among the extensions.
+	discoveryURL, err := resolver.SecurityExtension(scheme, "openIdConnectUrl")
+	if err != nil {
+		return api.NoSecurity(err, resolver.Log)
+	}
+
+	if discoveryURL == "" {
+		return api.NoSecurity(errors.SecurityNotFound(secName, "The 'openIdConnectUrl' field is missing.", nil), resolver.Log)
+	}
+
+	discoveryURL = secOpenIDConnect.Resolve(resolver.SpecFile, discoveryURL)
+
+	return secOpenIDConnect.New(secName, discoveryURL, resolver.SecurityScopes(secName), creds, resolver.Log)
+}
+
 // Response returns a Validator instance to test response correctness.
 // Since there may be multiple responses in a OAS spec file, it selects
 // on of them based on the arguments.
@@ -212,6 +328,46 @@ func (resolver *DataResolver) Response(status int64, CT string) contract.Validat
 	return v
 }
 
+// RequestViolations validates an outgoing request against the operation
+// parameter & request body schemas before it is sent. Undocumented query
+// parameters are reported as well, as they are most likely typos.
+func (resolver *DataResolver) RequestViolations(result *contract.OperationResult) []contract.Violation {
+	violations := ValidateRequest(resolver.Op, result.HTTPRequest, result.RequestBytes, result.PathParameters)
+
+	return append(violations, UnknownParameters(resolver.Op, resolver.Spec, result.HTTPRequest)...)
+}
+
+// ResponseProperties returns the sorted names of the top-level properties
+// of the operation response body schemas.
+func (resolver *DataResolver) ResponseProperties() []string {
+	names := map[string]bool{}
+
+	for _, resp := range *resolver.SpecResponses {
+		if resp == nil || resp.Value == nil {
+			continue
+		}
+
+		for _, mt := range resp.Value.Content {
+			if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+				continue
+			}
+
+			for name := range mt.Schema.Value.Properties {
+				names[name] = true
+			}
+		}
+	}
+
+	props := []string{}
+	for name := range names {
+		props = append(props, name)
+	}
+
+	sort.Strings(props)
+
+	return props
+}
+
 // MetaData populates the provided validator with expectations for HTTP status & content type.
 func (resolver *DataResolver) MetaData(status int64, CT string) (
 	int,
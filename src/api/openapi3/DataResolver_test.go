@@ -1,10 +1,12 @@
 package openapi3_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/x1n13y84issmd42/oasis/src/api/security"
 	secHTTP "github.com/x1n13y84issmd42/oasis/src/api/security/HTTP"
+	secOpenIDConnect "github.com/x1n13y84issmd42/oasis/src/api/security/OpenIDConnect"
 
 	kinopenapi3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
@@ -105,6 +107,14 @@ func Test_DataResolver(T *testing.T) {
 		assert.Equal(T, expected, actual)
 	})
 
+	T.Run("ResponseProperties", func(T *testing.T) {
+		resolver := openapi3.NewDataResolver(log.NewPlain(0), spec.OAS, nil, &spec.OAS.Paths["/pet/{petId}"].Get.Responses)
+
+		expected := []string{"category", "id", "name", "photoUrls", "status", "tags"}
+
+		assert.Equal(T, expected, resolver.ResponseProperties())
+	})
+
 	T.Run("MetaData/StatusError", func(T *testing.T) {
 		log := log.NewPlain(0)
 		resolver := openapi3.NewDataResolver(log, spec.OAS, nil, &spec.OAS.Paths["/pet/{petId}"].Get.Responses)
@@ -267,4 +277,19 @@ func Test_DataResolver_Security(T *testing.T) {
 
 		assert.IsType(T, expected, actual)
 	})
+	T.Run("Security/OpenIDConnect", func(T *testing.T) {
+		spec, err := openapi3.Load("../../../spec/test/oidc.yaml", log.NewPlain(0))
+		assert.Nil(T, err)
+
+		actual := spec.PathOperation("GET", "/pets/{id}").Resolve().Security("")
+
+		assert.IsType(T, &secOpenIDConnect.Security{}, actual)
+
+		sec := actual.(*secOpenIDConnect.Security)
+		assert.Equal(T, filepath.Join("..", "..", "..", "spec", "test", "idp", "openid-configuration.json"), sec.DiscoveryURL)
+		assert.Equal(T, []string{"openid", "pets"}, sec.Scopes)
+		assert.Equal(T, "app", sec.ClientID())
+
+		assert.IsType(T, api.NoSecurity(nil, log.NewPlain(0)), spec.PathOperation("GET", "/pets/{id}").Resolve().Security("broken"))
+	})
 }
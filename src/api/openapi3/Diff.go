@@ -0,0 +1,410 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// SpecDiff compares two versions of a spec and collects the changes.
+// Requests & responses are compared differently: a narrower request schema
+// breaks the clients which send what was allowed before, while a wider response
+// schema breaks the clients which don't expect the new values.
+type SpecDiff struct {
+	Old     *Spec
+	New     *Spec
+	Changes []contract.SpecChange
+}
+
+// Diff compares two versions of a spec.
+func Diff(oldSpec *Spec, newSpec *Spec) []contract.SpecChange {
+	diff := &SpecDiff{
+		Old:     oldSpec,
+		New:     newSpec,
+		Changes: []contract.SpecChange{},
+	}
+
+	diff.Operations()
+	diff.SecuritySchemes()
+
+	return diff.Changes
+}
+
+// Change records a change.
+func (diff *SpecDiff) Change(op string, location string, breaking bool, msg string, args ...interface{}) {
+	diff.Changes = append(diff.Changes, contract.SpecChange{
+		Operation: op,
+		Location:  location,
+		Message:   fmt.Sprintf(msg, args...),
+		Breaking:  breaking,
+	})
+}
+
+// Breaking tells whether there are breaking changes.
+func Breaking(changes []contract.SpecChange) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+var pathParamRx = regexp.MustCompile(`\{[^}]*\}`)
+
+// OperationKey identifies an operation regardless of it's path parameter names,
+// so "/pets/{id}" and "/pets/{petId}" are the same path.
+func OperationKey(op *Operation) string {
+	return op.RequestMethod + " " + pathParamRx.ReplaceAllString(op.RequestPath, "{}")
+}
+
+// OperationMap collects the spec operations by their keys.
+func OperationMap(spec *Spec) (map[string]*Operation, []string) {
+	ops := map[string]*Operation{}
+	keys := []string{}
+
+	for op := range spec.Operations() {
+		oasOp := op.(*Operation)
+		key := OperationKey(oasOp)
+		ops[key] = oasOp
+		keys = append(keys, key)
+	}
+
+	return ops, keys
+}
+
+// Operations compares the operations which are present in both versions
+// and reports the added & removed ones.
+func (diff *SpecDiff) Operations() {
+	oldOps, oldKeys := OperationMap(diff.Old)
+	newOps, newKeys := OperationMap(diff.New)
+
+	for _, key := range oldKeys {
+		oldOp := oldOps[key]
+		opName := oldOp.RequestMethod + " " + oldOp.RequestPath
+
+		newOp := newOps[key]
+		if newOp == nil {
+			diff.Change(opName, "", true, "The operation was removed.")
+			continue
+		}
+
+		opName = newOp.RequestMethod + " " + newOp.RequestPath
+
+		if !oldOp.SpecOp.Deprecated && newOp.SpecOp.Deprecated {
+			diff.Change(opName, "", false, "The operation was deprecated.")
+		}
+
+		diff.Parameters(opName, oldOp, newOp)
+		diff.RequestBody(opName, oldOp.SpecOp.RequestBody, newOp.SpecOp.RequestBody)
+		diff.Responses(opName, oldOp.SpecOp.Responses, newOp.SpecOp.Responses)
+		diff.Security(opName, diff.OperationSecurity(diff.Old, oldOp), diff.OperationSecurity(diff.New, newOp))
+	}
+
+	for _, key := range newKeys {
+		if oldOps[key] == nil {
+			newOp := newOps[key]
+			diff.Change(newOp.RequestMethod+" "+newOp.RequestPath, "", false, "The operation was added.")
+		}
+	}
+}
+
+// ParameterMap collects the operation parameters by their locations & names.
+// The operation parameters override the path ones. Path parameters
+// are identified by their positions in the path, so they can be renamed.
+func ParameterMap(op *Operation) (map[string]*openapi3.Parameter, []string) {
+	positions := map[string]int{}
+	for i, name := range pathParamRx.FindAllString(op.RequestPath, -1) {
+		positions[strings.Trim(name, "{}")] = i
+	}
+
+	res := map[string]*openapi3.Parameter{}
+
+	for _, params := range []openapi3.Parameters{op.SpecPath.Parameters, op.SpecOp.Parameters} {
+		for _, specP := range params {
+			if specP == nil || specP.Value == nil {
+				continue
+			}
+
+			key := specP.Value.In + " " + specP.Value.Name
+			if pos, ok := positions[specP.Value.Name]; ok && specP.Value.In == "path" {
+				key = fmt.Sprintf("path #%d", pos)
+			}
+
+			res[key] = specP.Value
+		}
+	}
+
+	keys := []string{}
+	for key := range res {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return res, keys
+}
+
+// Parameters compares the operation parameters.
+func (diff *SpecDiff) Parameters(opName string, oldOp *Operation, newOp *Operation) {
+	oldParams, oldKeys := ParameterMap(oldOp)
+	newParams, newKeys := ParameterMap(newOp)
+
+	for _, key := range oldKeys {
+		oldP := oldParams[key]
+		location := "parameter " + oldP.In + " " + oldP.Name
+
+		newP := newParams[key]
+		if newP == nil {
+			diff.Change(opName, location, false, "The parameter was removed.")
+			continue
+		}
+
+		location = "parameter " + newP.In + " " + newP.Name
+
+		if !oldP.Required && newP.Required {
+			diff.Change(opName, location, true, "The parameter became required.")
+		}
+
+		if oldP.Required && !newP.Required {
+			diff.Change(opName, location, false, "The parameter became optional.")
+		}
+
+		diff.Schema(opName, location, "", oldP.Schema, newP.Schema, true)
+	}
+
+	for _, key := range newKeys {
+		if oldParams[key] == nil {
+			newP := newParams[key]
+			location := "parameter " + newP.In + " " + newP.Name
+
+			if newP.Required {
+				diff.Change(opName, location, true, "A required parameter was added.")
+			} else {
+				diff.Change(opName, location, false, "An optional parameter was added.")
+			}
+		}
+	}
+}
+
+// RequestBody compares the operation request bodies.
+func (diff *SpecDiff) RequestBody(opName string, oldRef *openapi3.RequestBodyRef, newRef *openapi3.RequestBodyRef) {
+	location := "request body"
+
+	oldBody, newBody := &openapi3.RequestBody{}, &openapi3.RequestBody{}
+	if oldRef != nil && oldRef.Value != nil {
+		oldBody = oldRef.Value
+	}
+
+	if newRef != nil && newRef.Value != nil {
+		newBody = newRef.Value
+	}
+
+	if len(oldBody.Content) > 0 && len(newBody.Content) == 0 {
+		diff.Change(opName, location, false, "The request body was removed.")
+		return
+	}
+
+	if len(oldBody.Content) == 0 && len(newBody.Content) > 0 {
+		diff.Change(opName, location, newBody.Required, "A request body was added.")
+		return
+	}
+
+	if !oldBody.Required && newBody.Required {
+		diff.Change(opName, location, true, "The request body became required.")
+	}
+
+	diff.Content(opName, location, oldBody.Content, newBody.Content, true)
+}
+
+// Responses compares the operation responses.
+// Removing a successful response breaks clients, the others don't.
+func (diff *SpecDiff) Responses(opName string, oldResps openapi3.Responses, newResps openapi3.Responses) {
+	for _, status := range SortedKeys(oldResps) {
+		location := "response " + status
+
+		oldResp := oldResps[status]
+		newResp := newResps[status]
+
+		if newResp == nil || newResp.Value == nil {
+			diff.Change(opName, location, strings.HasPrefix(status, "2"), "The response was removed.")
+			continue
+		}
+
+		if oldResp == nil || oldResp.Value == nil {
+			continue
+		}
+
+		diff.Headers(opName, location, oldResp.Value.Headers, newResp.Value.Headers)
+		diff.Content(opName, location, oldResp.Value.Content, newResp.Value.Content, false)
+	}
+
+	for _, status := range SortedKeys(newResps) {
+		if oldResps[status] == nil {
+			diff.Change(opName, "response "+status, false, "The response was added.")
+		}
+	}
+}
+
+// Headers compares the response headers.
+func (diff *SpecDiff) Headers(opName string, location string, oldHeaders map[string]*openapi3.HeaderRef, newHeaders map[string]*openapi3.HeaderRef) {
+	for _, name := range SortedKeys(oldHeaders) {
+		hLocation := location + " header " + name
+
+		oldH := oldHeaders[name]
+		newH := newHeaders[name]
+
+		if oldH == nil || oldH.Value == nil {
+			continue
+		}
+
+		if newH == nil || newH.Value == nil {
+			diff.Change(opName, hLocation, oldH.Value.Required, "The header was removed.")
+			continue
+		}
+
+		if oldH.Value.Required && !newH.Value.Required {
+			diff.Change(opName, hLocation, true, "The header became optional.")
+		}
+
+		diff.Schema(opName, hLocation, "", oldH.Value.Schema, newH.Value.Schema, false)
+	}
+
+	for _, name := range SortedKeys(newHeaders) {
+		if oldHeaders[name] == nil {
+			diff.Change(opName, location+" header "+name, false, "The header was added.")
+		}
+	}
+}
+
+// Content compares the request or response content types & schemas.
+func (diff *SpecDiff) Content(opName string, location string, oldContent openapi3.Content, newContent openapi3.Content, request bool) {
+	for _, CT := range SortedKeys(oldContent) {
+		ctLocation := location + " " + CT
+
+		oldMT := oldContent[CT]
+		newMT := newContent[CT]
+
+		if newMT == nil {
+			diff.Change(opName, ctLocation, true, "The content type was removed.")
+			continue
+		}
+
+		if oldMT != nil {
+			diff.Schema(opName, ctLocation, "", oldMT.Schema, newMT.Schema, request)
+		}
+	}
+
+	for _, CT := range SortedKeys(newContent) {
+		if oldContent[CT] == nil {
+			diff.Change(opName, location+" "+CT, false, "The content type was added.")
+		}
+	}
+}
+
+// OperationSecurity returns the security requirements of an operation,
+// which are either it's own or the spec-wide ones.
+// Every requirement is represented by a list of scheme names.
+func (diff *SpecDiff) OperationSecurity(spec *Spec, op *Operation) []string {
+	reqs := spec.OAS.Security
+	if op.SpecOp.Security != nil {
+		reqs = *op.SpecOp.Security
+	}
+
+	res := []string{}
+	for _, req := range reqs {
+		names := SortedKeys(req)
+		if len(names) == 0 {
+			names = []string{"no security"}
+		}
+
+		res = append(res, strings.Join(names, " + "))
+	}
+
+	return res
+}
+
+// Security compares the security requirements of an operation.
+// Requirements are alternatives, so removing one of them breaks
+// the clients which use it, and adding one breaks nothing,
+// unless there were no requirements at all.
+func (diff *SpecDiff) Security(opName string, oldReqs []string, newReqs []string) {
+	has := func(reqs []string, req string) bool {
+		for _, r := range reqs {
+			if r == req {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, req := range oldReqs {
+		if !has(newReqs, req) {
+			diff.Change(opName, "security", true, "The %s security requirement was removed.", req)
+		}
+	}
+
+	for _, req := range newReqs {
+		if !has(oldReqs, req) {
+			diff.Change(opName, "security", len(oldReqs) == 0, "The %s security requirement was added.", req)
+		}
+	}
+}
+
+// SecuritySchemes compares the security schemes of the specs.
+func (diff *SpecDiff) SecuritySchemes() {
+	oldSchemes := diff.Old.OAS.Components.SecuritySchemes
+	newSchemes := diff.New.OAS.Components.SecuritySchemes
+
+	for _, name := range SortedKeys(oldSchemes) {
+		location := "security scheme " + name
+
+		oldS := oldSchemes[name]
+		newS := newSchemes[name]
+
+		if oldS == nil || oldS.Value == nil {
+			continue
+		}
+
+		if newS == nil || newS.Value == nil {
+			diff.Change("", location, false, "The security scheme was removed.")
+			continue
+		}
+
+		changed := func(what string, oldV string, newV string) {
+			if oldV != newV {
+				diff.Change("", location, true, "The %s changed from '%s' to '%s'.", what, oldV, newV)
+			}
+		}
+
+		changed("type", oldS.Value.Type, newS.Value.Type)
+		changed("scheme", oldS.Value.Scheme, newS.Value.Scheme)
+		changed("location", oldS.Value.In, newS.Value.In)
+		changed("name", oldS.Value.Name, newS.Value.Name)
+	}
+
+	for _, name := range SortedKeys(newSchemes) {
+		if oldSchemes[name] == nil {
+			diff.Change("", "security scheme "+name, false, "The security scheme was added.")
+		}
+	}
+}
+
+// SortedKeys returns the sorted keys of a map with string keys.
+func SortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
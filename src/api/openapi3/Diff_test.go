@@ -0,0 +1,40 @@
+package openapi3_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Diff(T *testing.T) {
+	load := func(name string) *openapi3.Spec {
+		spec, err := openapi3.Load("../../../spec/test/"+name, log.NewPlain(0))
+		assert.Nil(T, err)
+		return spec
+	}
+
+	changes := openapi3.Diff(load("diff-old.yaml"), load("diff-new.yaml"))
+
+	assert.Equal(T, []contract.SpecChange{
+		{Operation: "GET /pets/{petId}", Location: "parameter query fields", Message: "The parameter became required.", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json .name", Message: "The maxLength changed from 100 to 50.", Breaking: false},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json .status", Message: "The enum value 'lost' was added.", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json .tags", Message: "The property was removed.", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json .age", Message: "An optional property was added.", Breaking: false},
+		{Operation: "GET /pets/{petId}", Location: "security", Message: "The apiKey security requirement was added.", Breaking: true},
+		{Operation: "PUT /pets/{petId}", Location: "request body application/json .name", Message: "The maxLength changed from 100 to 50.", Breaking: true},
+		{Operation: "PUT /pets/{petId}", Location: "request body application/json .status", Message: "The enum value 'lost' was added.", Breaking: false},
+		{Operation: "PUT /pets/{petId}", Location: "request body application/json .tags", Message: "The property was removed.", Breaking: false},
+		{Operation: "PUT /pets/{petId}", Location: "request body application/json .age", Message: "An optional property was added.", Breaking: false},
+		{Operation: "PUT /pets/{petId}", Location: "security", Message: "The apiKey security requirement was added.", Breaking: true},
+		{Operation: "GET /stores", Message: "The operation was removed.", Breaking: true},
+		{Operation: "GET /owners", Message: "The operation was added.", Breaking: false},
+		{Location: "security scheme apiKey", Message: "The security scheme was added.", Breaking: false},
+	}, changes)
+
+	assert.True(T, openapi3.Breaking(changes))
+	assert.False(T, openapi3.Breaking(openapi3.Diff(load("diff-old.yaml"), load("diff-old.yaml"))))
+}
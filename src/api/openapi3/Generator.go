@@ -0,0 +1,180 @@
+package openapi3
+
+import (
+	"math"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Generate creates a value which conforms to the schema, to be used in responses.
+// It takes the schema examples, defaults & enum values when there are any,
+// otherwise the simplest values which fit the constraints.
+// Write-only properties are omitted, recursive schemas are generated until they recur.
+func Generate(ref *openapi3.SchemaRef) interface{} {
+	return generate(ref, map[*openapi3.Schema]bool{})
+}
+
+func generate(ref *openapi3.SchemaRef, stack map[*openapi3.Schema]bool) interface{} {
+	if ref == nil || ref.Value == nil || stack[ref.Value] {
+		return nil
+	}
+
+	s := ref.Value
+
+	stack[s] = true
+	defer delete(stack, s)
+
+	switch {
+	case s.Example != nil:
+		return s.Example
+
+	case s.Default != nil:
+		return s.Default
+
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+
+	case len(s.OneOf) > 0:
+		return generate(s.OneOf[0], stack)
+
+	case len(s.AnyOf) > 0:
+		return generate(s.AnyOf[0], stack)
+
+	case len(s.AllOf) > 0:
+		return generateAllOf(s, stack)
+	}
+
+	switch s.Type {
+	case "object":
+		return generateObject(s, stack)
+
+	case "array":
+		item := generate(s.Items, stack)
+		if item == nil {
+			return []interface{}{}
+		}
+
+		items := []interface{}{}
+		for i := uint64(0); i < s.MinItems || i == 0; i++ {
+			items = append(items, item)
+		}
+
+		return items
+
+	case "string":
+		return GenerateString(s)
+
+	case "integer":
+		return int64(math.Ceil(GenerateNumber(s, 1)))
+
+	case "number":
+		return GenerateNumber(s, 0.5)
+
+	case "boolean":
+		return true
+
+	case "":
+		if len(s.Properties) > 0 {
+			return generateObject(s, stack)
+		}
+	}
+
+	return nil
+}
+
+func generateObject(s *openapi3.Schema, stack map[*openapi3.Schema]bool) map[string]interface{} {
+	obj := map[string]interface{}{}
+
+	for _, name := range SortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		if prop == nil || prop.Value == nil || prop.Value.WriteOnly {
+			continue
+		}
+
+		if v := generate(prop, stack); v != nil {
+			obj[name] = v
+		}
+	}
+
+	return obj
+}
+
+// The allOf objects are merged, other values are taken as they are.
+func generateAllOf(s *openapi3.Schema, stack map[*openapi3.Schema]bool) interface{} {
+	obj := generateObject(s, stack)
+
+	for _, sub := range s.AllOf {
+		v := generate(sub, stack)
+
+		subObj, ok := v.(map[string]interface{})
+		if !ok {
+			if v != nil {
+				return v
+			}
+
+			continue
+		}
+
+		for name, pv := range subObj {
+			obj[name] = pv
+		}
+	}
+
+	return obj
+}
+
+// GenerateString creates a string which fits the schema format & length.
+func GenerateString(s *openapi3.Schema) string {
+	formats := map[string]string{
+		"date-time": "2020-01-01T00:00:00Z",
+		"date":      "2020-01-01",
+		"time":      "00:00:00",
+		"email":     "user@example.com",
+		"uuid":      "00000000-0000-4000-8000-000000000000",
+		"uri":       "http://example.com",
+		"url":       "http://example.com",
+		"hostname":  "example.com",
+		"ipv4":      "127.0.0.1",
+		"ipv6":      "::1",
+		"byte":      "c3RyaW5n",
+		"password":  "password",
+	}
+
+	v, ok := formats[s.Format]
+	if !ok {
+		v = "string"
+	}
+
+	if uint64(len(v)) < s.MinLength {
+		v += strings.Repeat("x", int(s.MinLength)-len(v))
+	}
+
+	if s.MaxLength != nil && uint64(len(v)) > *s.MaxLength {
+		v = v[:*s.MaxLength]
+	}
+
+	return v
+}
+
+// GenerateNumber creates a number within the schema bounds.
+// step is how far from an exclusive bound the number is.
+func GenerateNumber(s *openapi3.Schema, step float64) float64 {
+	v := 0.0
+
+	if s.Min != nil {
+		v = *s.Min
+		if s.ExclusiveMin {
+			v += step
+		}
+	}
+
+	if s.Max != nil && v > *s.Max {
+		v = *s.Max
+		if s.ExclusiveMax {
+			v -= step
+		}
+	}
+
+	return v
+}
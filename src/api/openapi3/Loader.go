@@ -10,8 +10,9 @@ func Load(path string, logger contract.Logger) (*Spec, error) {
 	oas, oasErr := openapi3.NewSwaggerLoader().LoadSwaggerFromFile(path)
 	if oasErr == nil {
 		return &Spec{
-			OAS: oas,
-			Log: logger,
+			OAS:  oas,
+			Log:  logger,
+			Path: path,
 		}, nil
 	}
 
@@ -0,0 +1,335 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// MockPreferences tell which of the documented responses a mock should answer with.
+// They come from the Prefer header, like "Prefer: status=404, example=notFound",
+// or from the query overrides, like "?__status=404&__example=notFound",
+// which take precedence. The content type comes from the Accept header
+// unless there is a "ct" preference. "dynamic=true" makes the mock
+// generate a response from the schema even when there are examples.
+type MockPreferences struct {
+	Status  string
+	CT      string
+	Example string
+	Dynamic bool
+}
+
+// ParsePreferences reads the mock preferences from the request.
+func ParsePreferences(req *http.Request) MockPreferences {
+	prefs := map[string]string{}
+
+	for _, header := range req.Header["Prefer"] {
+		for _, pref := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+			kv := strings.SplitN(strings.TrimSpace(pref), "=", 2)
+			if len(kv) == 2 {
+				prefs[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+			}
+		}
+	}
+
+	for name, values := range req.URL.Query() {
+		if strings.HasPrefix(name, "__") && len(values) > 0 {
+			prefs[strings.ToLower(strings.TrimPrefix(name, "__"))] = values[0]
+		}
+	}
+
+	return MockPreferences{
+		Status:  prefs["status"],
+		CT:      prefs["ct"],
+		Example: prefs["example"],
+		Dynamic: prefs["dynamic"] == "true",
+	}
+}
+
+// Mock is an HTTP handler which serves the spec operations.
+// Requests are validated against the parameter & request body schemas,
+// invalid ones are answered with 400 and a list of violations.
+// Valid ones get the documented examples or responses generated from the schemas.
+type Mock struct {
+	contract.EntityTrait
+	Spec *Spec
+}
+
+// NewMock creates a new Mock instance.
+func NewMock(spec *Spec, log contract.Logger) *Mock {
+	return &Mock{
+		EntityTrait: contract.Entity(log),
+		Spec:        spec,
+	}
+}
+
+// MockError is what the mock answers with when it can't serve a request.
+type MockError struct {
+	Errors []contract.Violation `json:"errors"`
+}
+
+// ServeHTTP serves a request.
+func (mock *Mock) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	status, header, body, violations := mock.Respond(req)
+
+	for name, values := range header {
+		w.Header()[name] = values
+	}
+
+	w.WriteHeader(status)
+	w.Write(body)
+
+	mock.Log.ServedRequest(req.Method, req.URL.String(), status)
+
+	for _, v := range violations {
+		mock.Log.Violation(v.Location, v.Message)
+	}
+}
+
+// Respond creates a response for the request.
+// The request violations are returned as well, if there are any.
+func (mock *Mock) Respond(req *http.Request) (int, http.Header, []byte, []contract.Violation) {
+	specPath, pathParams := mock.Spec.MatchPath(req.URL.Path)
+	if specPath == "" {
+		return mock.Error(http.StatusNotFound, contract.Violation{Message: "No spec path matches " + req.URL.Path + "."})
+	}
+
+	op := mock.Spec.PathOperation(req.Method, specPath)
+	if op == nil {
+		return mock.Error(http.StatusMethodNotAllowed, contract.Violation{Message: "The " + specPath + " path has no " + req.Method + " operation."})
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return mock.Error(http.StatusBadRequest, contract.Violation{Location: "request body", Message: err.Error()})
+	}
+
+	violations := ValidateRequest(op, req, body, pathParams)
+	if len(violations) > 0 {
+		return mock.Error(http.StatusBadRequest, violations...)
+	}
+
+	status, header, respBody, err := mock.Response(op, ParsePreferences(req), req.Header.Get("Accept"))
+	if err != nil {
+		if status != http.StatusNotAcceptable {
+			status = http.StatusBadRequest
+		}
+
+		return mock.Error(status, contract.Violation{Message: err.Error()})
+	}
+
+	return status, header, respBody, nil
+}
+
+// Error creates an error response.
+func (mock *Mock) Error(status int, violations ...contract.Violation) (int, http.Header, []byte, []contract.Violation) {
+	body, _ := json.Marshal(MockError{Errors: violations})
+
+	return status, http.Header{"Content-Type": []string{"application/json"}}, body, violations
+}
+
+// Response creates a documented response of the operation.
+// When none of the content types to answer with can encode the body,
+// the returned status is 406 along with the error.
+func (mock *Mock) Response(op *Operation, prefs MockPreferences, accept string) (int, http.Header, []byte, error) {
+	key, status, err := MockStatus(op.SpecOp.Responses, prefs.Status)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	specResp := op.SpecOp.Responses[key].Value
+	header := http.Header{}
+
+	for _, name := range SortedKeys(specResp.Headers) {
+		h := specResp.Headers[name]
+		if h == nil || h.Value == nil {
+			continue
+		}
+
+		v := h.Value.Example
+		if v == nil {
+			v = Generate(h.Value.Schema)
+		}
+
+		if v != nil {
+			header.Set(name, fmt.Sprint(v))
+		}
+	}
+
+	if len(specResp.Content) == 0 {
+		return status, header, []byte{}, nil
+	}
+
+	CTs, err := MockContentTypes(specResp.Content, prefs.CT, accept)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	for _, CT := range CTs {
+		v, err := MockBody(specResp.Content[CT], prefs)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		if body, ok := MockEncode(v, CT); ok {
+			header.Set("Content-Type", CT)
+			return status, header, body, nil
+		}
+	}
+
+	return http.StatusNotAcceptable, nil, nil, errors.Oops("The response can't be encoded as "+strings.Join(CTs, " or ")+", only JSON and string examples can.", nil)
+}
+
+// MockStatus selects the response to answer with and it's HTTP status.
+// By default it's the first successful one.
+func MockStatus(resps openapi3.Responses, preferred string) (string, int, error) {
+	status := func(key string) int {
+		if s, err := strconv.Atoi(key); err == nil {
+			return s
+		}
+
+		if len(key) == 3 && strings.HasSuffix(key, "XX") {
+			s, _ := strconv.Atoi(key[:1])
+			return s * 100
+		}
+
+		return http.StatusOK
+	}
+
+	keys := []string{}
+	for _, key := range SortedKeys(resps) {
+		if resps[key] != nil && resps[key].Value != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	if preferred != "" {
+		s, err := strconv.Atoi(preferred)
+		if err != nil {
+			return "", 0, errors.NotFound("Response", preferred, nil)
+		}
+
+		key := ResponseKey(resps, s)
+		if key == "" {
+			return "", 0, errors.NotFound("Response", preferred, nil)
+		}
+
+		return key, s, nil
+	}
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "2") {
+			return key, status(key), nil
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", 0, errors.NotFound("Response", "any", nil)
+	}
+
+	return keys[0], status(keys[0]), nil
+}
+
+// MockContentTypes lists the content types to answer with, the most preferred first.
+// The preferred one must be documented and is the only one. Otherwise the ones
+// from the Accept header go first when they match, then JSON when there is one.
+// The rest of the documented ones are listed only when the Accept header matches none.
+func MockContentTypes(content openapi3.Content, preferred string, accept string) ([]string, error) {
+	if preferred != "" {
+		if content[preferred] == nil {
+			return nil, errors.NotFound("Content type", preferred, nil)
+		}
+
+		return []string{preferred}, nil
+	}
+
+	CTs := SortedKeys(content)
+	result := []string{}
+
+	add := func(CT string) {
+		for _, r := range result {
+			if r == CT {
+				return
+			}
+		}
+
+		result = append(result, CT)
+	}
+
+	for _, r := range strings.Split(accept, ",") {
+		mediaRange, _, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil || mediaRange == "*/*" {
+			continue
+		}
+
+		for _, CT := range CTs {
+			if mediaRange == CT || (strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(CT, strings.TrimSuffix(mediaRange, "*"))) {
+				add(CT)
+			}
+		}
+	}
+
+	accepted := len(result) > 0
+
+	if content["application/json"] != nil {
+		add("application/json")
+	}
+
+	if !accepted {
+		for _, CT := range CTs {
+			add(CT)
+		}
+	}
+
+	return result, nil
+}
+
+// MockEncode encodes the body value as the content type. Any value can be encoded
+// as JSON, while the other content types take string values only, like XML examples.
+func MockEncode(v interface{}, CT string) ([]byte, bool) {
+	if strings.Contains(CT, "json") {
+		body, err := json.Marshal(v)
+		return body, err == nil
+	}
+
+	if s, ok := v.(string); ok {
+		return []byte(s), true
+	}
+
+	return nil, false
+}
+
+// MockBody selects the response body value: the preferred named example,
+// the media type example, the first named example or a value generated from the schema.
+func MockBody(mt *openapi3.MediaType, prefs MockPreferences) (interface{}, error) {
+	if prefs.Example != "" {
+		ex := mt.Examples[prefs.Example]
+		if ex == nil || ex.Value == nil {
+			return nil, errors.NotFound("Example", prefs.Example, nil)
+		}
+
+		return ex.Value.Value, nil
+	}
+
+	if !prefs.Dynamic {
+		if mt.Example != nil {
+			return mt.Example, nil
+		}
+
+		for _, name := range SortedKeys(mt.Examples) {
+			if ex := mt.Examples[name]; ex != nil && ex.Value != nil {
+				return ex.Value.Value, nil
+			}
+		}
+	}
+
+	return Generate(mt.Schema), nil
+}
@@ -0,0 +1,107 @@
+package openapi3_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Mock(T *testing.T) {
+	spec, err := openapi3.Load("../../../spec/test/pets.yaml", log.NewPlain(0))
+	assert.Nil(T, err)
+
+	mock := openapi3.NewMock(spec, log.NewPlain(0))
+
+	serve := func(method string, URL string, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, URL, strings.NewReader(body))
+		for name, v := range header {
+			req.Header.Set(name, v)
+		}
+
+		w := httptest.NewRecorder()
+		mock.ServeHTTP(w, req)
+
+		return w
+	}
+
+	T.Run("First named example", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1", "", nil)
+		assert.Equal(T, 200, w.Code)
+		assert.Equal(T, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(T, `{"name": "Tom", "kind": "cat"}`, w.Body.String())
+	})
+
+	T.Run("Preferred example", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1", "", map[string]string{"Prefer": "example=dog"})
+		assert.JSONEq(T, `{"name": "Rex", "kind": "dog"}`, w.Body.String())
+	})
+
+	T.Run("Query override", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1?__status=404", "", map[string]string{"Prefer": "status=200"})
+		assert.Equal(T, 404, w.Code)
+		assert.Equal(T, "", w.Body.String())
+	})
+
+	T.Run("Accepted content type", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1", "", map[string]string{"Accept": "text/*, */*"})
+		assert.Equal(T, "text/plain", w.Header().Get("Content-Type"))
+		assert.Equal(T, "Rex", w.Body.String())
+	})
+
+	T.Run("Unencodable content type", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/mine", "", map[string]string{"Accept": "application/xml"})
+		assert.Equal(T, 200, w.Code)
+		assert.Equal(T, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(T, `[{"name": "string", "kind": "dog", "age": 1}]`, w.Body.String())
+
+		w = serve("GET", "/v1/pets/mine?__ct=application/xml", "", nil)
+		assert.Equal(T, 406, w.Code)
+
+		w = serve("GET", "/v1/pets/feed", "", nil)
+		assert.Equal(T, 406, w.Code)
+		assert.JSONEq(T, `{"errors": [{"message": "The response can't be encoded as application/atom+xml, only JSON and string examples can."}]}`, w.Body.String())
+	})
+
+	T.Run("Generated", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1?__dynamic=true", "", nil)
+		assert.JSONEq(T, `{"name": "string", "kind": "dog", "age": 1}`, w.Body.String())
+
+		w = serve("GET", "/v1/pets/mine", "", nil)
+		assert.JSONEq(T, `[{"name": "string", "kind": "dog", "age": 1}]`, w.Body.String())
+
+		w = serve("POST", "/v1/pets", `{"name": "Rex"}`, map[string]string{"Content-Type": "application/json"})
+		assert.Equal(T, 201, w.Code)
+		assert.Equal(T, "http://example.com", w.Header().Get("Location"))
+	})
+
+	T.Run("Undocumented", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/1", "", map[string]string{"Prefer": "example=bird"})
+		assert.Equal(T, 400, w.Code)
+		assert.JSONEq(T, `{"errors": [{"message": "Example 'bird' not found in the spec."}]}`, w.Body.String())
+
+		w = serve("DELETE", "/v1/pets/1", "", nil)
+		assert.Equal(T, 405, w.Code)
+
+		w = serve("GET", "/v1/owners", "", nil)
+		assert.Equal(T, 404, w.Code)
+	})
+
+	T.Run("Invalid requests", func(T *testing.T) {
+		w := serve("GET", "/v1/pets/0", "", nil)
+		assert.Equal(T, 400, w.Code)
+		assert.JSONEq(T, `{"errors": [{"location": "path id", "message": "Number must be at least 1"}]}`, w.Body.String())
+
+		w = serve("POST", "/v1/pets", ``, map[string]string{"Content-Type": "application/json"})
+		assert.JSONEq(T, `{"errors": [{"location": "request body", "message": "The request body is required."}]}`, w.Body.String())
+
+		w = serve("POST", "/v1/pets", `{"name": "R"}`, map[string]string{"Content-Type": "application/json"})
+		assert.JSONEq(T, `{"errors": [{"location": "request body application/json /name", "message": "Minimum string length is 2"}]}`, w.Body.String())
+
+		w = serve("POST", "/v1/pets", `name=Rex`, map[string]string{"Content-Type": "text/plain"})
+		assert.JSONEq(T, `{"errors": [{"location": "request body", "message": "The content type 'text/plain' is not one of application/json."}]}`, w.Body.String())
+	})
+}
@@ -0,0 +1,189 @@
+package openapi3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Proxy is a reverse proxy which forwards the traffic to the Target
+// and validates every exchange against the spec on the way.
+// Requests are validated against the parameter & request body schemas,
+// responses with the DataResolver.Response validators.
+// Response bodies are streamed to the clients as they come and validated
+// after they are complete, in the background, so the traffic is never
+// blocked, delayed or altered. Violations are logged.
+type Proxy struct {
+	contract.EntityTrait
+	Spec   *Spec
+	Target *url.URL
+
+	validations sync.WaitGroup
+}
+
+// NewProxy creates a new Proxy instance.
+func NewProxy(spec *Spec, target *url.URL, log contract.Logger) *Proxy {
+	return &Proxy{
+		EntityTrait: contract.Entity(log),
+		Spec:        spec,
+		Target:      target,
+	}
+}
+
+// Wait waits for the validations of the exchanges proxied so far.
+func (proxy *Proxy) Wait() {
+	proxy.validations.Wait()
+}
+
+// ServeHTTP forwards a request to the target and validates the exchange.
+func (proxy *Proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		proxy.Log.Error(err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Every exchange is logged at once, so concurrent ones don't mix up.
+	logger := proxy.Log.Clone()
+	logger.Buffer(true)
+
+	URL := req.URL.String()
+
+	rp := httputil.NewSingleHostReverseProxy(proxy.Target)
+
+	director := rp.Director
+	rp.Director = func(outReq *http.Request) {
+		director(outReq)
+		outReq.Host = proxy.Target.Host
+	}
+
+	rp.ModifyResponse = func(resp *http.Response) error {
+		proxy.validations.Add(1)
+
+		resp.Body = &teeBody{
+			ReadCloser: resp.Body,
+			Done: func(respBody []byte, complete bool) {
+				go func() {
+					defer proxy.validations.Done()
+
+					logger.ServedRequest(req.Method, URL, resp.StatusCode)
+
+					if complete {
+						proxy.Validate(req, body, resp, respBody, logger)
+					} else {
+						logger.Violation("response body", "The response body was not streamed completely, so it is not validated.")
+					}
+
+					logger.Flush()
+				}()
+			},
+		}
+
+		return nil
+	}
+
+	rp.ErrorHandler = func(w http.ResponseWriter, outReq *http.Request, err error) {
+		logger.ServedRequest(req.Method, URL, http.StatusBadGateway)
+		logger.Error(err)
+		logger.Flush()
+
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	rp.ServeHTTP(w, req)
+}
+
+// Validate validates a proxied exchange and logs the violations.
+func (proxy *Proxy) Validate(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, logger contract.Logger) {
+	specPath, pathParams := proxy.Spec.MatchPath(req.URL.Path)
+	if specPath == "" {
+		logger.Violation("", "No spec path matches "+req.URL.Path+".")
+		return
+	}
+
+	op := proxy.Spec.PathOperation(req.Method, specPath)
+	if op == nil {
+		logger.Violation("", "The "+specPath+" path has no "+req.Method+" operation.")
+		return
+	}
+
+	for _, v := range ValidateRequest(op, req, reqBody, pathParams) {
+		logger.Violation(v.Location, v.Message)
+	}
+
+	proxy.ValidateResponse(op, req, reqBody, resp, respBody, logger)
+}
+
+// teeBody is a response body which keeps a copy of what is read from it.
+// Done is called with the copy once the body is closed, complete tells
+// whether the body has been read to the end.
+type teeBody struct {
+	io.ReadCloser
+	Done func(body []byte, complete bool)
+
+	buf      bytes.Buffer
+	complete bool
+	once     sync.Once
+}
+
+// Read reads from the body and keeps the copy.
+func (body *teeBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.buf.Write(p[:n])
+
+	if err == io.EOF {
+		body.complete = true
+	}
+
+	return n, err
+}
+
+// Close closes the body and calls Done.
+func (body *teeBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.once.Do(func() {
+		body.Done(body.buf.Bytes(), body.complete)
+	})
+
+	return err
+}
+
+// ValidateResponse validates the response of a proxied request
+// and logs the violations. Compressed bodies are validated decompressed.
+func (proxy *Proxy) ValidateResponse(
+	op *Operation,
+	req *http.Request,
+	reqBody []byte,
+	resp *http.Response,
+	respBody []byte,
+	logger contract.Logger,
+) bool {
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(bytes.NewReader(respBody))
+		if err == nil {
+			respBody, err = ioutil.ReadAll(gz)
+		}
+
+		if err != nil {
+			logger.Violation("response body", err.Error())
+			return false
+		}
+	}
+
+	violations := ValidateResponse(op, req, reqBody, resp, respBody, logger)
+	for _, v := range violations {
+		logger.Violation(v.Location, v.Message)
+	}
+
+	return len(violations) == 0
+}
@@ -0,0 +1,120 @@
+package openapi3_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Proxy(T *testing.T) {
+	spec, err := openapi3.Load("../../../spec/test/pets.yaml", log.NewPlain(0))
+	assert.Nil(T, err)
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(req.Method + " " + req.URL.String() + " " + string(body)))
+	}))
+	defer backend.Close()
+
+	target, _ := url.Parse(backend.URL)
+	proxy := openapi3.NewProxy(spec, target, log.NewPlain(0))
+
+	T.Run("Forwarding", func(T *testing.T) {
+		// Neither the request nor the response are valid, yet both pass through.
+		req := httptest.NewRequest("POST", "/v1/pets?a=1", strings.NewReader(`{"name": "R"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		proxy.ServeHTTP(w, req)
+
+		assert.Equal(T, http.StatusTeapot, w.Code)
+		assert.Equal(T, `POST /v1/pets?a=1 {"name": "R"}`, w.Body.String())
+	})
+
+	T.Run("Streaming", func(T *testing.T) {
+		// The second event is sent only after the client gets the first one.
+		received := make(chan bool)
+
+		streaming := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: 1\n\n"))
+			w.(http.Flusher).Flush()
+
+			<-received
+			w.Write([]byte("data: 2\n\n"))
+		}))
+		defer streaming.Close()
+
+		target, _ := url.Parse(streaming.URL)
+		proxy := openapi3.NewProxy(spec, target, log.NewPlain(0))
+		server := httptest.NewServer(proxy)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/v1/events")
+		assert.Nil(T, err)
+
+		events := bufio.NewReader(resp.Body)
+		line, _ := events.ReadString('\n')
+		assert.Equal(T, "data: 1\n", line)
+
+		close(received)
+		rest, _ := ioutil.ReadAll(events)
+		resp.Body.Close()
+		assert.Equal(T, "\ndata: 2\n\n", string(rest))
+
+		proxy.Wait()
+	})
+
+	T.Run("ResponseValidator", func(T *testing.T) {
+		op := spec.PathOperation("GET", "/pets/{id}")
+
+		validate := func(status int, CT string, body string) (bool, []contract.Violation) {
+			v, violations := openapi3.ResponseValidator(op, status, CT, log.NewPlain(0))
+			if v == nil {
+				return false, violations
+			}
+
+			result := v.Validate(&contract.OperationResult{
+				Success: true,
+				HTTPResponse: &http.Response{
+					StatusCode: status,
+					Header:     http.Header{"Content-Type": []string{CT}},
+				},
+				ResponseBytes: []byte(body),
+			})
+
+			return result.Success, violations
+		}
+
+		ok, violations := validate(200, "application/json; charset=utf-8", `{"name": "Rex", "kind": "dog"}`)
+		assert.True(T, ok)
+		assert.Empty(T, violations)
+
+		ok, violations = validate(200, "application/json", `{"name": "Rex", "kind": "cow"}`)
+		assert.False(T, ok)
+		assert.Empty(T, violations)
+
+		ok, violations = validate(404, "", ``)
+		assert.True(T, ok)
+		assert.Empty(T, violations)
+
+		ok, violations = validate(500, "application/json", `{}`)
+		assert.False(T, ok)
+		assert.Equal(T, []contract.Violation{{Location: "response", Message: "The status 500 is not documented."}}, violations)
+
+		ok, violations = validate(200, "text/html", `<p>Rex</p>`)
+		assert.False(T, ok)
+		assert.Equal(T, []contract.Violation{{Location: "response", Message: "The content type 'text/html' is not one of application/json, text/plain."}}, violations)
+	})
+}
@@ -0,0 +1,151 @@
+package openapi3
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// ValidateRequest checks an HTTP request against the operation parameters
+// & request body schemas. The path parameters are given separately because
+// only the caller knows how the URL path was matched.
+func ValidateRequest(op *Operation, req *http.Request, body []byte, pathParams map[string]string) []contract.Violation {
+	violations := []contract.Violation{}
+
+	params, keys := ParameterMap(op)
+	for _, key := range keys {
+		violations = append(violations, ValidateParameter(params[key], ParameterValues(params[key], req, pathParams))...)
+	}
+
+	return append(violations, ValidateRequestBody(op, req.Header.Get("Content-Type"), body)...)
+}
+
+// UnknownParameters reports the query parameters of a request which aren't
+// documented for the operation. The API key query parameters of the spec
+// security schemes are considered documented.
+func UnknownParameters(op *Operation, spec *openapi3.Swagger, req *http.Request) []contract.Violation {
+	known := map[string]bool{}
+
+	params, keys := ParameterMap(op)
+	for _, key := range keys {
+		if params[key].In == "query" {
+			known[params[key].Name] = true
+		}
+	}
+
+	for _, scheme := range spec.Components.SecuritySchemes {
+		if scheme != nil && scheme.Value != nil && scheme.Value.Type == "apiKey" && scheme.Value.In == "query" {
+			known[scheme.Value.Name] = true
+		}
+	}
+
+	violations := []contract.Violation{}
+
+	query := req.URL.Query()
+	for _, name := range SortedKeys(query) {
+		if !known[name] {
+			violations = append(violations, contract.Violation{Location: "query " + name, Message: "The parameter is not documented."})
+		}
+	}
+
+	return violations
+}
+
+// ValidateParameter checks the sent values of a parameter against it's schema.
+func ValidateParameter(p *openapi3.Parameter, values []string) []contract.Violation {
+	location := p.In + " " + p.Name
+
+	if len(values) == 0 {
+		if p.Required {
+			return []contract.Violation{{Location: location, Message: "The parameter is required."}}
+		}
+
+		return []contract.Violation{}
+	}
+
+	violations := []contract.Violation{}
+
+	if p.Schema != nil && p.Schema.Value != nil {
+		for _, v := range values {
+			if err := p.Schema.Value.VisitJSON(ParameterValue(v, p.Schema)); err != nil {
+				violations = append(violations, SchemaViolation(location, err))
+			}
+		}
+	}
+
+	return violations
+}
+
+// ValidateRequestBody checks a request body against the operation requestBody schemas.
+func ValidateRequestBody(op *Operation, CT string, body []byte) []contract.Violation {
+	if op.SpecOp.RequestBody == nil || op.SpecOp.RequestBody.Value == nil {
+		return []contract.Violation{}
+	}
+
+	specBody := op.SpecOp.RequestBody.Value
+
+	if len(body) == 0 {
+		if specBody.Required {
+			return []contract.Violation{{Location: "request body", Message: "The request body is required."}}
+		}
+
+		return []contract.Violation{}
+	}
+
+	CT = MediaType(CT)
+	location := "request body " + CT
+
+	mt := specBody.Content[CT]
+	if mt == nil {
+		return []contract.Violation{{
+			Location: "request body",
+			Message:  "The content type '" + CT + "' is not one of " + strings.Join(SortedKeys(specBody.Content), ", ") + ".",
+		}}
+	}
+
+	if mt.Schema == nil || mt.Schema.Value == nil {
+		return []contract.Violation{}
+	}
+
+	data := DecodeBody(CT, body)
+	if data == nil {
+		if strings.Contains(CT, "json") {
+			return []contract.Violation{{Location: location, Message: "The request body is not a valid JSON."}}
+		}
+
+		// Other content types can't be validated.
+		return []contract.Violation{}
+	}
+
+	// Form values are strings, they are converted to the property types first.
+	if CT == "application/x-www-form-urlencoded" {
+		form := data.(map[string]interface{})
+		for name, v := range form {
+			form[name] = ParameterValue(v.(string), mt.Schema.Value.Properties[name])
+		}
+	}
+
+	if err := mt.Schema.Value.VisitJSON(data); err != nil {
+		return []contract.Violation{SchemaViolation(location, err)}
+	}
+
+	return []contract.Violation{}
+}
+
+// SchemaViolation creates a Violation from a schema validation error,
+// adding the location of the invalid value within a document to location.
+func SchemaViolation(location string, err error) contract.Violation {
+	if serr, ok := err.(*openapi3.SchemaError); ok {
+		if pointer := serr.JSONPointer(); len(pointer) > 0 {
+			location += " /" + strings.Join(pointer, "/")
+		}
+
+		if serr.Reason != "" {
+			return contract.Violation{Location: location, Message: serr.Reason}
+		}
+	}
+
+	return contract.Violation{Location: location, Message: err.Error()}
+}
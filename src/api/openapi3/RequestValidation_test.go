@@ -0,0 +1,59 @@
+package openapi3_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_RequestViolations(T *testing.T) {
+	spec, err := openapi3.Load("../../../spec/test/pets.yaml", log.NewPlain(0))
+	assert.Nil(T, err)
+
+	result := func(method string, URL string, body string, pathParams map[string]string) *contract.OperationResult {
+		req, _ := http.NewRequest(method, URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		return &contract.OperationResult{
+			HTTPRequest:    req,
+			RequestBytes:   []byte(body),
+			PathParameters: pathParams,
+		}
+	}
+
+	T.Run("Valid", func(T *testing.T) {
+		op := spec.PathOperation("GET", "/pets/{id}")
+		violations := op.Resolve().RequestViolations(result("GET", "http://localhost/v1/pets/1?api_key=secret", "", map[string]string{"id": "1"}))
+
+		assert.Empty(T, violations)
+	})
+
+	T.Run("Parameters", func(T *testing.T) {
+		op := spec.PathOperation("GET", "/pets/{id}")
+		violations := op.Resolve().RequestViolations(result("GET", "http://localhost/v1/pets/0?statsu=sold", "", map[string]string{"id": "0"}))
+
+		assert.Equal(T, []contract.Violation{
+			{Location: "path id", Message: "Number must be at least 1"},
+			{Location: "query statsu", Message: "The parameter is not documented."},
+		}, violations)
+	})
+
+	T.Run("Body", func(T *testing.T) {
+		op := spec.PathOperation("POST", "/pets")
+
+		violations := op.Resolve().RequestViolations(result("POST", "http://localhost/v1/pets", `{"age": 1}`, nil))
+		assert.Equal(T, []contract.Violation{
+			{Location: "request body application/json", Message: "Property 'name' is missing"},
+		}, violations)
+
+		violations = op.Resolve().RequestViolations(result("POST", "http://localhost/v1/pets", `{"name": "Rex", "age": "one"}`, nil))
+		assert.Equal(T, []contract.Violation{
+			{Location: "request body application/json /age", Message: "Field must be set to integer or not be present"},
+		}, violations)
+	})
+}
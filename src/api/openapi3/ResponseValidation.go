@@ -0,0 +1,89 @@
+package openapi3
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+// ResponseValidator creates a validator for an actual response of the operation.
+// Documented responses are validated with the DataResolver.Response expectations,
+// which report the failures to log. Undocumented statuses & content types
+// are returned as violations instead, as there is nothing to validate against.
+func ResponseValidator(op *Operation, status int, CT string, log contract.Logger) (contract.Validator, []contract.Violation) {
+	key := ResponseKey(op.SpecOp.Responses, status)
+	if key == "" {
+		return nil, []contract.Violation{{
+			Location: "response",
+			Message:  fmt.Sprintf("The status %d is not documented.", status),
+		}}
+	}
+
+	specResp := op.SpecOp.Responses[key]
+
+	// The "2XX" & "default" responses are resolved under the actual status.
+	resolver := NewDataResolver(log, op.Resolver.Spec, op, &openapi3.Responses{strconv.Itoa(status): specResp})
+
+	if len(specResp.Value.Content) == 0 {
+		v := test.NewValidator(log)
+		v.Expect(expect.Status(status, log))
+
+		err := resolver.Headers(specResp.Value, v)
+		if err != nil {
+			return nil, []contract.Violation{{Location: "response", Message: err.Error()}}
+		}
+
+		return v, []contract.Violation{}
+	}
+
+	CT = MediaType(CT)
+	if specResp.Value.Content[CT] == nil {
+		return nil, []contract.Violation{{
+			Location: "response",
+			Message:  "The content type '" + CT + "' is not one of " + strings.Join(SortedKeys(specResp.Value.Content), ", ") + ".",
+		}}
+	}
+
+	return resolver.Response(int64(status), CT), []contract.Violation{}
+}
+
+// ValidateResponse checks an HTTP response of the operation against the spec
+// with the ResponseValidator, collecting the expectation failures as violations.
+func ValidateResponse(op *Operation, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, log contract.Logger) []contract.Violation {
+	vlog := test.NewViolationLogger(log)
+
+	v, violations := ResponseValidator(op, resp.StatusCode, resp.Header.Get("Content-Type"), vlog)
+	if v == nil {
+		return violations
+	}
+
+	result := v.Validate(&contract.OperationResult{
+		Success:       true,
+		HTTPRequest:   req,
+		HTTPResponse:  resp,
+		RequestBytes:  reqBody,
+		ResponseBytes: respBody,
+	})
+
+	// Schema errors come in no particular order, so the reports are sorted to be repeatable.
+	sort.SliceStable(vlog.Violations, func(i, j int) bool {
+		a, b := vlog.Violations[i], vlog.Violations[j]
+		return a.Location < b.Location || (a.Location == b.Location && a.Message < b.Message)
+	})
+
+	violations = append(violations, vlog.Violations...)
+
+	// Some expectations fail silently, like the schema ones on empty bodies.
+	if !result.Success && len(violations) == 0 {
+		violations = append(violations, contract.Violation{Location: "response", Message: "The response doesn't conform to the spec."})
+	}
+
+	return violations
+}
@@ -0,0 +1,101 @@
+package openapi3
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathRx creates a regular expression which matches the URL paths
+// of the spec path, like "/pets/{id}", and captures it's parameters in order.
+func PathRx(specPath string) *regexp.Regexp {
+	rx := "^"
+	last := 0
+
+	for _, loc := range pathParamRx.FindAllStringIndex(specPath, -1) {
+		rx += regexp.QuoteMeta(specPath[last:loc[0]]) + "([^/]+)"
+		last = loc[1]
+	}
+
+	return regexp.MustCompile(rx + regexp.QuoteMeta(specPath[last:]) + "$")
+}
+
+// BasePaths returns the path parts of the spec server URLs, like "/v2".
+func (spec *Spec) BasePaths() []string {
+	res := []string{}
+
+	for _, server := range spec.OAS.Servers {
+		u, err := url.Parse(server.URL)
+		if err == nil && u.Path != "" && u.Path != "/" {
+			res = append(res, strings.TrimSuffix(u.Path, "/"))
+		}
+	}
+
+	return res
+}
+
+// MatchPath finds the spec path which matches the URL path and extracts
+// the path parameters from it. The spec server base paths are taken into account.
+// Literal path segments win over parameters, so "/pets/mine" is preferred
+// over "/pets/{id}". It returns an empty string when nothing matches.
+func (spec *Spec) MatchPath(urlPath string) (string, map[string]string) {
+	candidates := []string{urlPath}
+	for _, base := range spec.BasePaths() {
+		if strings.HasPrefix(urlPath, base+"/") {
+			candidates = append(candidates, strings.TrimPrefix(urlPath, base))
+		}
+	}
+
+	specPaths := []string{}
+	for specPath := range spec.OAS.Paths {
+		specPaths = append(specPaths, specPath)
+	}
+
+	sort.Strings(specPaths)
+
+	bestPath, bestParams := "", map[string]string{}
+	bestCount := -1
+
+	for _, specPath := range specPaths {
+		rx := PathRx(specPath)
+		count := len(pathParamRx.FindAllString(specPath, -1))
+
+		for _, candidate := range candidates {
+			m := rx.FindStringSubmatch(candidate)
+			if m == nil || (bestCount >= 0 && count >= bestCount) {
+				continue
+			}
+
+			bestPath, bestCount = specPath, count
+			bestParams = map[string]string{}
+
+			for i, name := range pathParamRx.FindAllString(specPath, -1) {
+				v, err := url.PathUnescape(m[i+1])
+				if err != nil {
+					v = m[i+1]
+				}
+
+				bestParams[strings.Trim(name, "{}")] = v
+			}
+		}
+	}
+
+	return bestPath, bestParams
+}
+
+// PathOperation returns the operation of the spec path with the method,
+// or nil when there is none.
+func (spec *Spec) PathOperation(method string, specPath string) *Operation {
+	pathItem := spec.OAS.Paths[specPath]
+	if pathItem == nil {
+		return nil
+	}
+
+	oasOp := pathItem.GetOperation(strings.ToUpper(method))
+	if oasOp == nil {
+		return nil
+	}
+
+	return spec.MakeOperation(strings.ToUpper(method), oasOp, specPath, pathItem).(*Operation)
+}
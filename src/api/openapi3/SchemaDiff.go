@@ -0,0 +1,277 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Schema compares two versions of a request or a response schema.
+// path is a location within the schema, like ".owner.tags[]".
+// A narrower request schema is a breaking change, as well as a wider response schema.
+func (diff *SpecDiff) Schema(
+	opName string,
+	location string,
+	path string,
+	oldRef *openapi3.SchemaRef,
+	newRef *openapi3.SchemaRef,
+	request bool,
+) {
+	diff.schema(opName, location, path, oldRef, newRef, request, map[[2]*openapi3.Schema]bool{})
+}
+
+func (diff *SpecDiff) schema(
+	opName string,
+	location string,
+	path string,
+	oldRef *openapi3.SchemaRef,
+	newRef *openapi3.SchemaRef,
+	request bool,
+	visited map[[2]*openapi3.Schema]bool,
+) {
+	if oldRef == nil || newRef == nil || oldRef.Value == nil || newRef.Value == nil {
+		return
+	}
+
+	oldS, newS := oldRef.Value, newRef.Value
+
+	// Recursive schemas are compared once.
+	if visited[[2]*openapi3.Schema{oldS, newS}] {
+		return
+	}
+
+	visited[[2]*openapi3.Schema{oldS, newS}] = true
+
+	loc := strings.TrimSpace(location + " " + path)
+
+	// Narrowing changes break requests, widening ones break responses.
+	narrowed := func(msg string, args ...interface{}) {
+		diff.Change(opName, loc, request, msg, args...)
+	}
+
+	widened := func(msg string, args ...interface{}) {
+		diff.Change(opName, loc, !request, msg, args...)
+	}
+
+	if oldS.Type != newS.Type {
+		switch {
+		case oldS.Type == "integer" && newS.Type == "number":
+			widened("The type changed from integer to number.")
+
+		case oldS.Type == "number" && newS.Type == "integer":
+			narrowed("The type changed from number to integer.")
+
+		case oldS.Type == "":
+			narrowed("The type %s was set.", newS.Type)
+
+		case newS.Type == "":
+			widened("The type %s was unset.", oldS.Type)
+
+		default:
+			diff.Change(opName, loc, true, "The type changed from %s to %s.", oldS.Type, newS.Type)
+		}
+	}
+
+	if oldS.Format != newS.Format {
+		switch {
+		case oldS.Format == "":
+			narrowed("The format %s was set.", newS.Format)
+
+		case newS.Format == "":
+			widened("The format %s was unset.", oldS.Format)
+
+		default:
+			diff.Change(opName, loc, true, "The format changed from %s to %s.", oldS.Format, newS.Format)
+		}
+	}
+
+	if oldS.Nullable && !newS.Nullable {
+		narrowed("The value is not nullable anymore.")
+	}
+
+	if !oldS.Nullable && newS.Nullable {
+		widened("The value became nullable.")
+	}
+
+	if oldS.Pattern != newS.Pattern {
+		diff.Change(opName, loc, oldS.Pattern != "" || request, "The pattern changed from '%s' to '%s'.", oldS.Pattern, newS.Pattern)
+	}
+
+	diff.Enum(oldS.Enum, newS.Enum, narrowed, widened)
+
+	diff.Bound("minimum", oldS.Min, newS.Min, true, narrowed, widened)
+	diff.Bound("maximum", oldS.Max, newS.Max, false, narrowed, widened)
+	diff.Bound("minLength", Float(&oldS.MinLength), Float(&newS.MinLength), true, narrowed, widened)
+	diff.Bound("maxLength", Float(oldS.MaxLength), Float(newS.MaxLength), false, narrowed, widened)
+	diff.Bound("minItems", Float(&oldS.MinItems), Float(&newS.MinItems), true, narrowed, widened)
+	diff.Bound("maxItems", Float(oldS.MaxItems), Float(newS.MaxItems), false, narrowed, widened)
+
+	diff.Properties(opName, location, path, oldS, newS, request, visited)
+
+	diff.schema(opName, location, path+"[]", oldS.Items, newS.Items, request, visited)
+
+	diff.Alternatives(opName, location, path, "oneOf", oldS.OneOf, newS.OneOf, request, visited)
+	diff.Alternatives(opName, location, path, "anyOf", oldS.AnyOf, newS.AnyOf, request, visited)
+
+	for i := 0; i < len(oldS.AllOf) && i < len(newS.AllOf); i++ {
+		diff.schema(opName, location, path, oldS.AllOf[i], newS.AllOf[i], request, visited)
+	}
+}
+
+// Properties compares the object properties of schemas.
+func (diff *SpecDiff) Properties(
+	opName string,
+	location string,
+	path string,
+	oldS *openapi3.Schema,
+	newS *openapi3.Schema,
+	request bool,
+	visited map[[2]*openapi3.Schema]bool,
+) {
+	required := func(s *openapi3.Schema, name string) bool {
+		for _, r := range s.Required {
+			if r == name {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, name := range SortedKeys(oldS.Properties) {
+		propPath := path + "." + name
+		loc := strings.TrimSpace(location + " " + propPath)
+
+		if newS.Properties[name] == nil {
+			diff.Change(opName, loc, !request, "The property was removed.")
+			continue
+		}
+
+		oldRequired, newRequired := required(oldS, name), required(newS, name)
+
+		if !oldRequired && newRequired {
+			diff.Change(opName, loc, request, "The property became required.")
+		}
+
+		if oldRequired && !newRequired {
+			diff.Change(opName, loc, !request, "The property became optional.")
+		}
+
+		diff.schema(opName, location, propPath, oldS.Properties[name], newS.Properties[name], request, visited)
+	}
+
+	for _, name := range SortedKeys(newS.Properties) {
+		if oldS.Properties[name] == nil {
+			loc := strings.TrimSpace(location + " " + path + "." + name)
+
+			if required(newS, name) {
+				diff.Change(opName, loc, request, "A required property was added.")
+			} else {
+				diff.Change(opName, loc, false, "An optional property was added.")
+			}
+		}
+	}
+}
+
+// Alternatives compares the oneOf or anyOf schema lists.
+// Removed alternatives break requests, added ones break responses.
+func (diff *SpecDiff) Alternatives(
+	opName string,
+	location string,
+	path string,
+	kind string,
+	oldAlts []*openapi3.SchemaRef,
+	newAlts []*openapi3.SchemaRef,
+	request bool,
+	visited map[[2]*openapi3.Schema]bool,
+) {
+	loc := strings.TrimSpace(location + " " + path)
+
+	if len(newAlts) < len(oldAlts) {
+		diff.Change(opName, loc, request, "The number of %s alternatives decreased from %d to %d.", kind, len(oldAlts), len(newAlts))
+	}
+
+	if len(newAlts) > len(oldAlts) {
+		diff.Change(opName, loc, !request, "The number of %s alternatives increased from %d to %d.", kind, len(oldAlts), len(newAlts))
+	}
+
+	for i := 0; i < len(oldAlts) && i < len(newAlts); i++ {
+		diff.schema(opName, location, fmt.Sprintf("%s(%s #%d)", path, kind, i), oldAlts[i], newAlts[i], request, visited)
+	}
+}
+
+// Enum compares the enumerations of schemas.
+func (diff *SpecDiff) Enum(oldEnum []interface{}, newEnum []interface{}, narrowed func(string, ...interface{}), widened func(string, ...interface{})) {
+	if len(oldEnum) == 0 && len(newEnum) > 0 {
+		narrowed("The value was restricted to an enum.")
+		return
+	}
+
+	if len(oldEnum) > 0 && len(newEnum) == 0 {
+		widened("The enum restriction was removed.")
+		return
+	}
+
+	has := func(enum []interface{}, v interface{}) bool {
+		for _, ev := range enum {
+			if fmt.Sprint(ev) == fmt.Sprint(v) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, v := range oldEnum {
+		if !has(newEnum, v) {
+			narrowed("The enum value '%v' was removed.", v)
+		}
+	}
+
+	for _, v := range newEnum {
+		if !has(oldEnum, v) {
+			widened("The enum value '%v' was added.", v)
+		}
+	}
+}
+
+// Bound compares the minimum or maximum constraints of schemas.
+func (diff *SpecDiff) Bound(
+	name string,
+	oldV *float64,
+	newV *float64,
+	lower bool,
+	narrowed func(string, ...interface{}),
+	widened func(string, ...interface{}),
+) {
+	switch {
+	case oldV == nil && newV == nil:
+		return
+
+	case oldV == nil:
+		narrowed("The %s of %v was set.", name, *newV)
+
+	case newV == nil:
+		widened("The %s of %v was unset.", name, *oldV)
+
+	case *oldV == *newV:
+		return
+
+	case (*newV > *oldV) == lower:
+		narrowed("The %s changed from %v to %v.", name, *oldV, *newV)
+
+	default:
+		widened("The %s changed from %v to %v.", name, *oldV, *newV)
+	}
+}
+
+// Float converts an optional constraint value, zeros are considered unset.
+func Float(v *uint64) *float64 {
+	if v == nil || *v == 0 {
+		return nil
+	}
+
+	f := float64(*v)
+	return &f
+}
@@ -12,8 +12,9 @@ import (
 
 // Spec is an OAS3-backed API test spec.
 type Spec struct {
-	Log contract.Logger
-	OAS *openapi3.Swagger
+	Log  contract.Logger
+	OAS  *openapi3.Swagger
+	Path string
 }
 
 // Operations returns an iterable channel with operations.
@@ -113,6 +114,7 @@ func (spec *Spec) MakeOperation(
 	}
 
 	op.Resolver = NewDataResolver(op.Log, spec.OAS, op, &oasOp.Responses)
+	op.Resolver.SpecFile = spec.Path
 	op.OperationPrototype.Operation = op
 
 	URL := params.URL(oasPath, op.Log)
@@ -24,17 +24,17 @@ func New(name string, location string, paramName string, value string, logger co
 	switch location {
 	case "cookie":
 		return &Cookie{
-			Security{name, paramName, params.Value(value), logger},
+			Security{name, paramName, params.Expression(value, logger), logger},
 		}
 
 	case "header":
 		return &Header{
-			Security{name, paramName, params.Value(value), logger},
+			Security{name, paramName, params.Expression(value, logger), logger},
 		}
 
 	case "query":
 		return &Query{
-			Security{name, paramName, params.Value(value), logger},
+			Security{name, paramName, params.Expression(value, logger), logger},
 		}
 	}
 
@@ -63,3 +63,11 @@ func (sec *Security) SetUsername(v contract.ParameterAccess) {
 // SetPassword does nothig.
 func (sec *Security) SetPassword(v contract.ParameterAccess) {
 }
+
+// SetClientID does nothing.
+func (sec *Security) SetClientID(v contract.ParameterAccess) {
+}
+
+// SetClientSecret does nothing.
+func (sec *Security) SetClientSecret(v contract.ParameterAccess) {
+}
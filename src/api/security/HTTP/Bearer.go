@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Bearer implements a Bearer HTTP authentication.
+type Bearer struct {
+	Security
+}
+
+// Enrich adds a bearer token to the Authorization request header.
+func (sec *Bearer) Enrich(req *http.Request, log contract.Logger) {
+	log.UsingSecurity(sec)
+
+	if t := sec.Token(); t != "" {
+		req.Header["Authorization"] = append(req.Header["Authorization"], Authorization("Bearer", t))
+	} else {
+		log.SecurityHasNoData(sec)
+	}
+}
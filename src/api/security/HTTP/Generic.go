@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Generic implements the registered HTTP authentication schemes
+// which have no dedicated implementation. It sends a token with the scheme name.
+type Generic struct {
+	Security
+	Scheme string
+}
+
+// Enrich adds a token to the Authorization request header.
+func (sec *Generic) Enrich(req *http.Request, log contract.Logger) {
+	log.UsingSecurity(sec)
+
+	if t := sec.Token(); t != "" {
+		req.Header["Authorization"] = append(req.Header["Authorization"], Authorization(sec.Scheme, t))
+	} else {
+		log.SecurityHasNoData(sec)
+	}
+}
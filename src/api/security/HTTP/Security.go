@@ -37,17 +37,37 @@ type WWWAuthenticate struct {
 	QoP    WWWAuthenticateQoP
 }
 
+// Schemes are the names of the HTTP authentication schemes
+// from the IANA registry, by their lowercase names.
+var Schemes = map[string]string{
+	"basic":         "Basic",
+	"bearer":        "Bearer",
+	"concealed":     "Concealed",
+	"digest":        "Digest",
+	"dpop":          "DPoP",
+	"gnap":          "GNAP",
+	"hoba":          "HOBA",
+	"mutual":        "Mutual",
+	"negotiate":     "Negotiate",
+	"oauth":         "OAuth",
+	"privatetoken":  "PrivateToken",
+	"scram-sha-1":   "SCRAM-SHA-1",
+	"scram-sha-256": "SCRAM-SHA-256",
+	"vapid":         "vapid",
+}
+
 // New creates a new HTTP security.
+// Scheme names are case-insensitive.
 func New(name string, scheme string, token string, username string, password string, logger contract.Logger) contract.Security {
-	switch scheme {
+	switch strings.ToLower(scheme) {
 	case "basic":
 		return &Basic{
 			Security{
 				Name:     name,
-				Token:    params.Value(token),
+				Token:    params.Expression(token, logger),
 				Log:      logger,
-				Username: params.Value(username),
-				Password: params.Value(password),
+				Username: params.Expression(username, logger),
+				Password: params.Expression(password, logger),
 			},
 		}
 
@@ -55,15 +75,45 @@ func New(name string, scheme string, token string, username string, password str
 		return &Digest{
 			Security{
 				Name:  name,
-				Token: params.Value(token),
+				Token: params.Expression(token, logger),
+				Log:   logger,
+			},
+		}
+
+	case "bearer":
+		return &Bearer{
+			Security{
+				Name:  name,
+				Token: params.Expression(token, logger),
+				Log:   logger,
+			},
+		}
+	}
+
+	if canonical, ok := Schemes[strings.ToLower(scheme)]; ok {
+		return &Generic{
+			Security: Security{
+				Name:  name,
+				Token: params.Expression(token, logger),
 				Log:   logger,
 			},
+			Scheme: canonical,
 		}
 	}
 
 	return api.NoSecurity(errors.New("Unknown security scheme '"+scheme+"'"), logger)
 }
 
+// Authorization makes an Authorization header value from the scheme & the token.
+// Tokens which already have the scheme prefix are used as they are.
+func Authorization(scheme string, token string) string {
+	if len(token) > len(scheme) && strings.EqualFold(token[:len(scheme)+1], scheme+" ") {
+		return token
+	}
+
+	return scheme + " " + token
+}
+
 // Probe makes a request to a URL which is (supposedly) protected
 // by an HTTP Basic or Digest authentication scheme in order to obtain an authentication
 // request from the server.
@@ -147,3 +197,11 @@ func (sec *Security) SetUsername(v contract.ParameterAccess) {
 func (sec *Security) SetPassword(v contract.ParameterAccess) {
 	sec.Password = v
 }
+
+// SetClientID does nothing.
+func (sec *Security) SetClientID(v contract.ParameterAccess) {
+}
+
+// SetClientSecret does nothing.
+func (sec *Security) SetClientSecret(v contract.ParameterAccess) {
+}
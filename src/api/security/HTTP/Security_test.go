@@ -47,6 +47,48 @@ func Test_Digest(T *testing.T) {
 	assert.Equal(T, token, req.Header.Get("Authorization"))
 }
 
+func Test_Bearer(T *testing.T) {
+	log := log.NewPlain(0)
+
+	for token, expected := range map[string]string{
+		"00112233":        "Bearer 00112233",
+		"Bearer 00112233": "Bearer 00112233",
+		"bearer 00112233": "bearer 00112233",
+	} {
+		sec := &http.Bearer{
+			Security: http.Security{
+				Name:  "test sec",
+				Token: params.Value(token),
+				Log:   log,
+			},
+		}
+
+		req, _ := gohttp.NewRequest("GET", "example.com", nil)
+
+		sec.Enrich(req, log)
+
+		assert.Equal(T, expected, req.Header.Get("Authorization"))
+	}
+}
+
+func Test_Generic(T *testing.T) {
+	log := log.NewPlain(0)
+	sec := &http.Generic{
+		Security: http.Security{
+			Name:  "test sec",
+			Token: params.Value("00112233"),
+			Log:   log,
+		},
+		Scheme: "DPoP",
+	}
+
+	req, _ := gohttp.NewRequest("GET", "example.com", nil)
+
+	sec.Enrich(req, log)
+
+	assert.Equal(T, "DPoP 00112233", req.Header.Get("Authorization"))
+}
+
 func Test_New(T *testing.T) {
 	token := "0011223344"
 
@@ -70,6 +112,27 @@ func Test_New(T *testing.T) {
 		}
 	})
 
+	T.Run("Bearer", func(T *testing.T) {
+		sec := http.New("test sec", "Bearer", token, "", "", log.NewPlain(0))
+		tsec, ok := sec.(*http.Bearer)
+		assert.True(T, ok)
+
+		if ok {
+			assert.Equal(T, token, tsec.Token())
+		}
+	})
+
+	T.Run("Generic", func(T *testing.T) {
+		sec := http.New("test sec", "negotiate", token, "", "", log.NewPlain(0))
+		tsec, ok := sec.(*http.Generic)
+		assert.True(T, ok)
+
+		if ok {
+			assert.Equal(T, "Negotiate", tsec.Scheme)
+			assert.Equal(T, token, tsec.Token())
+		}
+	})
+
 	T.Run("Invalid", func(T *testing.T) {
 		sec := http.New("test sec", "INVALID SCHEMA", "", "", "", log.NewPlain(0))
 		_, ok := sec.(*api.NullSecurity)
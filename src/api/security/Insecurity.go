@@ -32,6 +32,14 @@ func (sec *Empty) SetUsername(v contract.ParameterAccess) {
 func (sec *Empty) SetPassword(v contract.ParameterAccess) {
 }
 
+// SetClientID does nothing for Insecurity.
+func (sec *Empty) SetClientID(v contract.ParameterAccess) {
+}
+
+// SetClientSecret does nothing for Insecurity.
+func (sec *Empty) SetClientSecret(v contract.ParameterAccess) {
+}
+
 // Enrich does nothing for Insecurity.
 func (sec *Empty) Enrich(req *http.Request, log contract.Logger) {
 	sec.Log.UsingSecurity(sec)
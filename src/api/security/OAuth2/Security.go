@@ -0,0 +1,276 @@
+package oauth2
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Flows are the token endpoints of the OAuth2 flows which need no user interaction.
+// An empty URL means the API doesn't support the flow.
+// Refresh tokens are exchanged at the Refresh endpoint.
+type Flows struct {
+	Password          string
+	ClientCredentials string
+	Refresh           string
+}
+
+// Credentials are what the tokens are obtained with.
+// A Token is used as it is, without obtaining anything.
+// A RefreshToken is exchanged for an access token, it's how flows
+// which need user interaction (like the authorization code one) are used.
+// Otherwise the password flow is used when there is a username,
+// and the client credentials flow when there is not.
+type Credentials struct {
+	Token        string
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+}
+
+// Security implements the 'oauth2' security type.
+// It obtains access tokens from the token endpoints and sends them
+// in the Authorization header as bearer tokens.
+type Security struct {
+	Name         string
+	Flows        Flows
+	Scopes       []string
+	Token        contract.ParameterAccess
+	RefreshToken contract.ParameterAccess
+	ClientID     contract.ParameterAccess
+	ClientSecret contract.ParameterAccess
+	Username     contract.ParameterAccess
+	Password     contract.ParameterAccess
+	Log          contract.Logger
+
+	// Verify checks the obtained tokens, when set.
+	Verify func(token *Token) error
+}
+
+// New creates a new OAuth2 security.
+func New(name string, flows Flows, scopes []string, creds Credentials, logger contract.Logger) *Security {
+	return &Security{
+		Name:         name,
+		Flows:        flows,
+		Scopes:       scopes,
+		Token:        params.Expression(creds.Token, logger),
+		RefreshToken: params.Expression(creds.RefreshToken, logger),
+		ClientID:     params.Expression(creds.ClientID, logger),
+		ClientSecret: params.Expression(creds.ClientSecret, logger),
+		Username:     params.Expression(creds.Username, logger),
+		Password:     params.Expression(creds.Password, logger),
+		Log:          logger,
+	}
+}
+
+// Enrich obtains an access token and adds it to the Authorization request header.
+func (sec *Security) Enrich(req *http.Request, log contract.Logger) {
+	log.UsingSecurity(sec)
+
+	if !sec.HasCredentials() {
+		log.SecurityHasNoData(sec)
+		return
+	}
+
+	token, err := sec.AccessToken(log)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	if token == "" {
+		log.SecurityHasNoData(sec)
+		return
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// HasCredentials tells whether there is anything to obtain tokens with.
+// The flows which need user interaction, like the implicit one, have none
+// unless they are given a refresh token.
+func (sec *Security) HasCredentials() bool {
+	return sec.Token() != "" || sec.RefreshToken() != "" || sec.Username() != "" || sec.ClientID() != ""
+}
+
+// AccessToken returns an access token to use in requests.
+// Tokens are cached per security scheme & scopes, and obtained anew
+// when they expire, with the refresh tokens when there are any.
+func (sec *Security) AccessToken(log contract.Logger) (string, error) {
+	if t := sec.Token(); t != "" {
+		return t, nil
+	}
+
+	token, err := sec.CachedToken(log)
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+// TokenKey returns the key of the security tokens in the cache.
+func (sec *Security) TokenKey() string {
+	return TokenKey(sec.Name, sec.Flows, Credentials{
+		ClientID:     sec.ClientID(),
+		ClientSecret: sec.ClientSecret(),
+		Username:     sec.Username(),
+		Password:     sec.Password(),
+		RefreshToken: sec.RefreshToken(),
+	}, sec.Scopes)
+}
+
+// CachedToken returns a valid token from the cache, obtaining it first when needed.
+func (sec *Security) CachedToken(log contract.Logger) (*Token, error) {
+	key := sec.TokenKey()
+
+	// Concurrent operations wait for the same token instead of obtaining their own.
+	lock := Tokens.KeyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cached := Tokens.Get(key)
+	if cached != nil && !cached.Expired() {
+		return cached, nil
+	}
+
+	var token *Token
+	var err error
+
+	if cached != nil && cached.RefreshToken != "" && sec.Flows.Refresh != "" {
+		token, err = sec.Refresh(cached.RefreshToken, log)
+		if err != nil {
+			// The refresh token might have expired too.
+			log.Error(err)
+		}
+	}
+
+	if token == nil {
+		token, err = sec.Obtain(log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Refresh tokens may be issued once, so the old one is kept when there is no new one.
+	if token.RefreshToken == "" && cached != nil {
+		token.RefreshToken = cached.RefreshToken
+	}
+
+	Tokens.Set(key, token)
+
+	return token, nil
+}
+
+// Obtain obtains a new token using the available credentials.
+func (sec *Security) Obtain(log contract.Logger) (*Token, error) {
+	if rt := sec.RefreshToken(); rt != "" && sec.Flows.Refresh != "" {
+		return sec.Refresh(rt, log)
+	}
+
+	form := url.Values{}
+	if len(sec.Scopes) > 0 {
+		form.Set("scope", strings.Join(sec.Scopes, " "))
+	}
+
+	if u := sec.Username(); u != "" && sec.Flows.Password != "" {
+		form.Set("grant_type", "password")
+		form.Set("username", u)
+		form.Set("password", sec.Password())
+
+		return sec.Request(sec.Flows.Password, form, log)
+	}
+
+	if sec.Flows.ClientCredentials != "" && sec.ClientID() != "" {
+		form.Set("grant_type", "client_credentials")
+
+		return sec.Request(sec.Flows.ClientCredentials, form, log)
+	}
+
+	return nil, errors.Oops("The '"+sec.Name+"' security has no credentials for any of the supported OAuth2 flows.", nil)
+}
+
+// Refresh exchanges a refresh token for a new access token.
+func (sec *Security) Refresh(refreshToken string, log contract.Logger) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+
+	if len(sec.Scopes) > 0 {
+		form.Set("scope", strings.Join(sec.Scopes, " "))
+	}
+
+	return sec.Request(sec.Flows.Refresh, form, log)
+}
+
+// Request requests a token from the endpoint.
+func (sec *Security) Request(tokenURL string, form url.Values, log contract.Logger) (*Token, error) {
+	log.ObtainingToken(sec.Name, form.Get("grant_type"), tokenURL)
+
+	token, err := RequestToken(tokenURL, form, sec.ClientID(), sec.ClientSecret())
+	if err != nil {
+		return nil, err
+	}
+
+	if sec.Verify != nil {
+		err = sec.Verify(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// Or creates a ParameterAccess which returns the v value,
+// or the fallback one when v is empty.
+func Or(v contract.ParameterAccess, fallback contract.ParameterAccess) contract.ParameterAccess {
+	return func() string {
+		if s := v(); s != "" {
+			return s
+		}
+
+		return fallback()
+	}
+}
+
+// GetName returns name.
+func (sec Security) GetName() string {
+	return sec.Name
+}
+
+// SetValue does nothing.
+func (sec *Security) SetValue(v contract.ParameterAccess) {
+}
+
+// SetToken sets Token. Empty values keep the current one,
+// so scripts may override some of the credentials only.
+func (sec *Security) SetToken(v contract.ParameterAccess) {
+	sec.Token = Or(v, sec.Token)
+}
+
+// SetUsername sets Username.
+func (sec *Security) SetUsername(v contract.ParameterAccess) {
+	sec.Username = Or(v, sec.Username)
+}
+
+// SetPassword sets Password.
+func (sec *Security) SetPassword(v contract.ParameterAccess) {
+	sec.Password = Or(v, sec.Password)
+}
+
+// SetClientID sets ClientID.
+func (sec *Security) SetClientID(v contract.ParameterAccess) {
+	sec.ClientID = Or(v, sec.ClientID)
+}
+
+// SetClientSecret sets ClientSecret.
+func (sec *Security) SetClientSecret(v contract.ParameterAccess) {
+	sec.ClientSecret = Or(v, sec.ClientSecret)
+}
@@ -0,0 +1,210 @@
+package oauth2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	gohttp "net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	oauth2 "github.com/x1n13y84issmd42/oasis/src/api/security/OAuth2"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+func Test_OAuth2(T *testing.T) {
+	grants := []string{}
+
+	server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, req *gohttp.Request) {
+		req.ParseForm()
+		grant := req.PostForm.Get("grant_type")
+		clientID, clientSecret, _ := req.BasicAuth()
+
+		grants = append(grants, fmt.Sprintf("%s %s:%s %s", grant, clientID, clientSecret, req.PostForm.Get("scope")))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case grant == "password" && req.PostForm.Get("password") != "secret":
+			w.WriteHeader(gohttp.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "Wrong password."})
+
+		case grant == "refresh_token":
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "refreshed " + req.PostForm.Get("refresh_token"), "expires_in": 3600})
+
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": grant, "refresh_token": "rt", "expires_in": 3600})
+		}
+	}))
+	defer server.Close()
+
+	flows := oauth2.Flows{
+		Password:          server.URL,
+		ClientCredentials: server.URL,
+		Refresh:           server.URL,
+	}
+
+	authorization := func(sec *oauth2.Security) string {
+		req, _ := gohttp.NewRequest("GET", "http://example.com", nil)
+		sec.Enrich(req, log.NewPlain(0))
+
+		return req.Header.Get("Authorization")
+	}
+
+	T.Run("Client credentials", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, []string{"write", "read"}, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+
+		assert.Equal(T, "Bearer client_credentials", authorization(sec))
+		assert.Equal(T, "Bearer client_credentials", authorization(sec))
+
+		// The same scopes in a different order share the token.
+		sec2 := oauth2.New("auth", flows, []string{"read", "write"}, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer client_credentials", authorization(sec2))
+
+		assert.Equal(T, []string{"client_credentials app:s3cr3t write read"}, grants)
+	})
+
+	T.Run("Password", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", Username: "user", Password: "secret"}, log.NewPlain(0))
+
+		assert.Equal(T, "Bearer password", authorization(sec))
+		assert.Equal(T, []string{"password : "}, grants)
+
+		oauth2.Tokens = oauth2.NewTokenCache()
+		sec.SetPassword(params.Value("wrong"))
+
+		_, err := sec.AccessToken(log.NewPlain(0))
+		assert.NotNil(T, err)
+		assert.Contains(T, err.Error(), "invalid_grant Wrong password.")
+	})
+
+	T.Run("Refresh", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer client_credentials", authorization(sec))
+
+		oauth2.Tokens.Get(sec.TokenKey()).Expires = time.Now()
+
+		assert.Equal(T, "Bearer refreshed rt", authorization(sec))
+		assert.Equal(T, "rt", oauth2.Tokens.Get(sec.TokenKey()).RefreshToken)
+
+		// A refresh token from the spec or a script is exchanged right away.
+		oauth2.Tokens = oauth2.NewTokenCache()
+		sec = oauth2.New("auth", flows, nil, oauth2.Credentials{RefreshToken: "given"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer refreshed given", authorization(sec))
+	})
+
+	T.Run("Token", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		sec.SetToken(params.Value("abc"))
+		sec.SetClientID(params.Value(""))
+
+		assert.Equal(T, "Bearer abc", authorization(sec))
+		assert.Empty(T, grants)
+		assert.Equal(T, "app", sec.ClientID())
+	})
+
+	T.Run("Same name", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		other := oauth2.New("auth", oauth2.Flows{ClientCredentials: server.URL + "/other"}, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		user := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", Username: "user", Password: "secret"}, log.NewPlain(0))
+
+		authorization(sec)
+		authorization(other)
+		authorization(user)
+
+		assert.Equal(T, 3, len(grants))
+	})
+
+	T.Run("Secrets", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", Username: "user", Password: "secret"}, log.NewPlain(0))
+		authorization(sec)
+		authorization(sec)
+
+		// A script overriding the password gets a token of it's own.
+		sec.SetPassword(params.Value("wrong"))
+		authorization(sec)
+
+		assert.Equal(T, []string{"password : ", "password : "}, grants)
+		assert.NotContains(T, sec.TokenKey(), "wrong")
+	})
+
+	T.Run("Key locks", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+
+		// A token request of one key doesn't block the other keys.
+		lock := oauth2.Tokens.KeyLock("slow")
+		lock.Lock()
+		defer lock.Unlock()
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer client_credentials", authorization(sec))
+		assert.Same(T, oauth2.Tokens.KeyLock(sec.TokenKey()), oauth2.Tokens.KeyLock(sec.TokenKey()))
+	})
+
+	T.Run("No credentials", func(T *testing.T) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+
+		// Like the implicit flow, which needs a user.
+		sec := oauth2.New("auth", oauth2.Flows{}, nil, oauth2.Credentials{}, log.NewPlain(0))
+		assert.False(T, sec.HasCredentials())
+		assert.Equal(T, "", authorization(sec))
+		assert.Empty(T, grants)
+
+		sec = oauth2.New("auth", oauth2.Flows{ClientCredentials: server.URL}, nil, oauth2.Credentials{Username: "user"}, log.NewPlain(0))
+
+		_, err := sec.AccessToken(log.NewPlain(0))
+		assert.NotNil(T, err)
+		assert.Equal(T, "", authorization(sec))
+	})
+
+	T.Run("Cassettes", func(T *testing.T) {
+		dir, _ := ioutil.TempDir("", "oasis")
+		defer os.RemoveAll(dir)
+		defer func() { test.Cassettes = nil }()
+
+		oauth2.Tokens = oauth2.NewTokenCache()
+		grants = []string{}
+		test.Cassettes = test.NewCassetteDeck(dir, true)
+
+		sec := oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer client_credentials", authorization(sec))
+
+		// Neither the secrets nor the tokens are stored.
+		cassette, _ := ioutil.ReadFile(test.Cassettes.Path(test.SecurityCassette))
+		assert.NotContains(T, string(cassette), "s3cr3t")
+		assert.NotContains(T, string(cassette), `"rt"`)
+		assert.NotContains(T, string(cassette), "client_credentials\\\"")
+
+		oauth2.Tokens = oauth2.NewTokenCache()
+		test.Cassettes = test.NewCassetteDeck(dir, false)
+
+		// The requests match by the presence of the secrets rather than their values.
+		sec = oauth2.New("auth", flows, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "0th3r"}, log.NewPlain(0))
+		assert.Equal(T, "Bearer "+test.Redacted, authorization(sec))
+		assert.Equal(T, 1, len(grants))
+	})
+}
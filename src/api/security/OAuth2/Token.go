@@ -0,0 +1,177 @@
+package oauth2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// Client is the HTTP client for the token requests. Tokens are obtained
+// while their cache key is locked, so the requests must not hang.
+var Client = &http.Client{Timeout: 30 * time.Second}
+
+// ExpiryLeeway is how long before the actual expiration tokens are considered expired,
+// so they don't expire on the way to the server.
+const ExpiryLeeway = 10 * time.Second
+
+// Token is an access token obtained from a token endpoint.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+
+	// Expires is computed from ExpiresIn when the token is received.
+	// Zero means the token never expires.
+	Expires time.Time `json:"-"`
+}
+
+// Expired tells whether the token is expired or about to.
+func (token *Token) Expired() bool {
+	return !token.Expires.IsZero() && time.Now().Add(ExpiryLeeway).After(token.Expires)
+}
+
+// TokenError is an error response of a token endpoint.
+type TokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// TokenCache keeps the obtained tokens, so they are reused by all the operations
+// which use the same security scheme with the same scopes.
+type TokenCache struct {
+	sync.Mutex
+	tokens map[string]*Token
+	locks  map[string]*sync.Mutex
+}
+
+// Tokens is the tokens obtained during a run.
+var Tokens = NewTokenCache()
+
+// NewTokenCache creates a new TokenCache instance.
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		tokens: map[string]*Token{},
+		locks:  map[string]*sync.Mutex{},
+	}
+}
+
+// TokenKey identifies the tokens of a security scheme with a set of scopes,
+// obtained from the token endpoints with a set of credentials.
+// Different specs may have the schemes of the same name, so the endpoints are a part of the key.
+// The secrets are hashed, so changing any of them obtains a new token.
+func TokenKey(name string, flows Flows, creds Credentials, scopes []string) string {
+	sorted := append([]string{}, scopes...)
+	sort.Strings(sorted)
+
+	secrets := sha256.Sum256([]byte(strings.Join([]string{creds.ClientSecret, creds.Password, creds.RefreshToken}, "\x00")))
+
+	return strings.Join(append([]string{
+		name,
+		flows.Password,
+		flows.ClientCredentials,
+		flows.Refresh,
+		creds.ClientID,
+		creds.Username,
+		hex.EncodeToString(secrets[:]),
+	}, sorted...), " ")
+}
+
+// KeyLock returns the lock of the key. Concurrent operations lock it to wait
+// for the same token instead of obtaining their own, while the tokens
+// of other keys are obtained independently.
+func (cache *TokenCache) KeyLock(key string) *sync.Mutex {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if cache.locks[key] == nil {
+		cache.locks[key] = &sync.Mutex{}
+	}
+
+	return cache.locks[key]
+}
+
+// Get returns a cached token, expired or not, or nil.
+func (cache *TokenCache) Get(key string) *Token {
+	cache.Lock()
+	defer cache.Unlock()
+
+	return cache.tokens[key]
+}
+
+// Set caches a token.
+func (cache *TokenCache) Set(key string, token *Token) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.tokens[key] = token
+}
+
+// RequestToken requests a token from a token endpoint with the grant parameters.
+// The client credentials are sent with HTTP Basic authentication when there is
+// a client secret, public clients only send their ID in the form.
+func RequestToken(tokenURL string, form url.Values, clientID string, clientSecret string) (*Token, error) {
+	if clientID != "" && clientSecret == "" {
+		form.Set("client_id", clientID)
+	}
+
+	reqBody := []byte(form.Encode())
+
+	req, err := http.NewRequest("POST", tokenURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	resp, body, err := test.Send(test.SecurityCassette, Client, req, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		tokenErr := TokenError{}
+		json.Unmarshal(body, &tokenErr)
+
+		msg := fmt.Sprintf("The %s token request to %s has failed with the status %d", form.Get("grant_type"), tokenURL, resp.StatusCode)
+		if tokenErr.Error != "" {
+			msg += ": " + strings.TrimSpace(tokenErr.Error+" "+tokenErr.Description)
+		}
+
+		return nil, errors.Oops(msg+".", nil)
+	}
+
+	token := &Token{}
+	err = json.Unmarshal(body, token)
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the token response from "+tokenURL+".", err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, errors.Oops("The token response from "+tokenURL+" has no access token.", nil)
+	}
+
+	if token.ExpiresIn > 0 {
+		token.Expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
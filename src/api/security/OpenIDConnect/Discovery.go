@@ -0,0 +1,188 @@
+package openidconnect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	oauth2 "github.com/x1n13y84issmd42/oasis/src/api/security/OAuth2"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// Configuration is the OpenID Connect provider metadata
+// obtained from the discovery document.
+type Configuration struct {
+	Issuer                           string   `json:"issuer"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	GrantTypesSupported              []string `json:"grant_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Supports tells whether the provider supports the grant type.
+// Providers which don't list their grant types are assumed to support any.
+func (config *Configuration) Supports(grant string) bool {
+	if len(config.GrantTypesSupported) == 0 {
+		return true
+	}
+
+	for _, g := range config.GrantTypesSupported {
+		if g == grant {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DiscoveryCache keeps the fetched provider configurations & key sets,
+// so they are fetched once per run.
+type DiscoveryCache struct {
+	sync.Mutex
+	configurations map[string]*Configuration
+	keys           map[string]*JWKS
+}
+
+// Discovered is the provider configurations & key sets fetched during a run.
+var Discovered = NewDiscoveryCache()
+
+// NewDiscoveryCache creates a new DiscoveryCache instance.
+func NewDiscoveryCache() *DiscoveryCache {
+	return &DiscoveryCache{
+		configurations: map[string]*Configuration{},
+		keys:           map[string]*JWKS{},
+	}
+}
+
+// Configuration returns the provider configuration from the discovery document location.
+// The endpoints of local documents may be relative to the document.
+func (cache *DiscoveryCache) Configuration(location string) (*Configuration, error) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if config := cache.configurations[location]; config != nil {
+		return config, nil
+	}
+
+	config := &Configuration{}
+	err := FetchJSON(location, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.TokenEndpoint == "" {
+		return nil, errors.Oops("The OpenID Connect configuration at "+location+" has no token endpoint.", nil)
+	}
+
+	config.TokenEndpoint = Resolve(location, config.TokenEndpoint)
+	if config.JWKSURI != "" {
+		config.JWKSURI = Resolve(location, config.JWKSURI)
+	}
+
+	cache.configurations[location] = config
+
+	return config, nil
+}
+
+// Keys returns the key set from the location.
+func (cache *DiscoveryCache) Keys(location string) (*JWKS, error) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if keys := cache.keys[location]; keys != nil {
+		return keys, nil
+	}
+
+	keys := &JWKS{}
+	err := FetchJSON(location, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.keys[location] = keys
+
+	return keys, nil
+}
+
+// IsURL tells whether the location is an HTTP URL rather than a local file.
+func IsURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// Resolve resolves a location relative to the base one.
+func Resolve(base string, location string) string {
+	if IsURL(location) {
+		return location
+	}
+
+	if IsURL(base) {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return location
+		}
+
+		ref, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+
+		return baseURL.ResolveReference(ref).String()
+	}
+
+	location = strings.TrimPrefix(location, "file://")
+	if filepath.IsAbs(location) {
+		return location
+	}
+
+	return filepath.Join(filepath.Dir(strings.TrimPrefix(base, "file://")), location)
+}
+
+// FetchJSON reads a JSON document from an HTTP URL or a local file into v.
+// Local files stand in for the actual providers, when those are unavailable.
+func FetchJSON(location string, v interface{}) error {
+	var data []byte
+	var err error
+
+	if IsURL(location) {
+		data, err = Get(location)
+	} else {
+		data, err = ioutil.ReadFile(strings.TrimPrefix(location, "file://"))
+	}
+
+	if err != nil {
+		return errors.Oops("Cannot fetch "+location+".", err)
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return errors.Oops("Cannot parse the document at "+location+".", err)
+	}
+
+	return nil
+}
+
+// Get requests the URL and returns the response body.
+// The requests go through the cassettes like the token requests do.
+func Get(URL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := test.Send(test.SecurityCassette, oauth2.Client, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the status is %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
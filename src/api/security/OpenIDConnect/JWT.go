@@ -0,0 +1,309 @@
+package openidconnect
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers the SHA-256 hash.
+	_ "crypto/sha512" // Registers the SHA-384 & SHA-512 hashes.
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// ClockSkew is how much the clocks of the provider and Oasis may differ
+// when the time claims of ID tokens are checked.
+const ClockSkew = time.Minute
+
+// JWK is a JSON Web Key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS is a JSON Web Key set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKey creates a public key from the JWK.
+func (jwk JWK) PublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, errors.Oops("The RSA key '"+jwk.Kid+"' has an invalid modulus.", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, errors.Oops("The RSA key '"+jwk.Kid+"' has an invalid exponent.", err)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+
+		curve := curves[jwk.Crv]
+		if curve == nil {
+			return nil, errors.Oops("The EC key '"+jwk.Kid+"' has an unsupported curve '"+jwk.Crv+"'.", nil)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, errors.Oops("The EC key '"+jwk.Kid+"' has an invalid X coordinate.", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, errors.Oops("The EC key '"+jwk.Kid+"' has an invalid Y coordinate.", err)
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+
+	return nil, errors.Oops("The key '"+jwk.Kid+"' has an unsupported type '"+jwk.Kty+"'.", nil)
+}
+
+// Audience is the "aud" claim, which is either a string or an array of strings.
+type Audience []string
+
+// UnmarshalJSON unmarshals both forms of the claim.
+func (aud *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*aud = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	err := json.Unmarshal(data, &multiple)
+	*aud = Audience(multiple)
+
+	return err
+}
+
+// Contains tells whether the audience contains the client.
+func (aud Audience) Contains(clientID string) bool {
+	for _, a := range aud {
+		if a == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Claims are the ID token claims Oasis checks.
+type Claims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        Audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	Expires         int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	NotBefore       int64    `json:"nbf"`
+}
+
+// IDToken is a parsed ID token.
+type IDToken struct {
+	Header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	Claims Claims
+
+	// Signed is the signed part of the token, the header & the payload.
+	Signed    string
+	Signature []byte
+}
+
+// ParseIDToken parses a compact serialized ID token.
+func ParseIDToken(raw string) (*IDToken, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.Oops("The ID token is not a signed JWT.", nil)
+	}
+
+	token := &IDToken{
+		Signed: parts[0] + "." + parts[1],
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err == nil {
+		err = json.Unmarshal(header, &token.Header)
+	}
+
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the ID token header.", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err == nil {
+		err = json.Unmarshal(payload, &token.Claims)
+	}
+
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the ID token claims.", err)
+	}
+
+	token.Signature, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the ID token signature.", err)
+	}
+
+	return token, nil
+}
+
+// Hash returns the hash function of the signing algorithm.
+func Hash(alg string) crypto.Hash {
+	if len(alg) < 3 {
+		return 0
+	}
+
+	switch alg[len(alg)-3:] {
+	case "256":
+		return crypto.SHA256
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	}
+
+	return 0
+}
+
+// VerifySignature verifies the token signature with the keys from the set.
+// The HMAC signatures are verified with the client secret.
+func (token *IDToken) VerifySignature(keys *JWKS, clientSecret string) error {
+	alg := token.Header.Alg
+
+	if len(alg) != 5 || Hash(alg) == 0 {
+		return errors.Oops("The ID token signing algorithm '"+alg+"' is not supported.", nil)
+	}
+
+	hash := Hash(alg)
+	hasher := hash.New()
+	hasher.Write([]byte(token.Signed))
+	digest := hasher.Sum(nil)
+
+	if alg[:2] == "HS" {
+		mac := hmac.New(hash.New, []byte(clientSecret))
+		mac.Write([]byte(token.Signed))
+
+		if clientSecret != "" && hmac.Equal(mac.Sum(nil), token.Signature) {
+			return nil
+		}
+
+		return errors.Oops("The ID token signature is invalid.", nil)
+	}
+
+	if keys == nil {
+		return errors.Oops("The provider has no keys to verify the ID token signature with.", nil)
+	}
+
+	for _, jwk := range keys.Keys {
+		if token.Header.Kid != "" && jwk.Kid != token.Header.Kid {
+			continue
+		}
+
+		if jwk.Use != "" && jwk.Use != "sig" || jwk.Alg != "" && jwk.Alg != alg {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+
+		switch pub := key.(type) {
+		case *rsa.PublicKey:
+			if alg[:2] == "RS" && rsa.VerifyPKCS1v15(pub, hash, digest, token.Signature) == nil {
+				return nil
+			}
+
+			if alg[:2] == "PS" && rsa.VerifyPSS(pub, hash, digest, token.Signature, nil) == nil {
+				return nil
+			}
+
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			if alg[:2] == "ES" && len(token.Signature) == 2*size {
+				r := new(big.Int).SetBytes(token.Signature[:size])
+				s := new(big.Int).SetBytes(token.Signature[size:])
+
+				if ecdsa.Verify(pub, digest, r, s) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return errors.Oops("The ID token signature doesn't match any of the provider keys.", nil)
+}
+
+// VerifyClaims checks that the token is issued by the issuer, for the client,
+// and is valid at the moment.
+func (token *IDToken) VerifyClaims(issuer string, clientID string, now time.Time) error {
+	claims := token.Claims
+
+	if claims.Issuer != issuer {
+		return errors.Oops("The ID token is issued by '"+claims.Issuer+"' instead of '"+issuer+"'.", nil)
+	}
+
+	if claims.Subject == "" {
+		return errors.Oops("The ID token has no subject.", nil)
+	}
+
+	if !claims.Audience.Contains(clientID) {
+		return errors.Oops("The ID token is not issued for the client '"+clientID+"'.", nil)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != "" && claims.AuthorizedParty != clientID {
+		return errors.Oops("The ID token is authorized for '"+claims.AuthorizedParty+"' instead of '"+clientID+"'.", nil)
+	}
+
+	if claims.Expires == 0 || now.Add(-ClockSkew).After(time.Unix(claims.Expires, 0)) {
+		return errors.Oops("The ID token is expired.", nil)
+	}
+
+	if claims.NotBefore != 0 && now.Add(ClockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return errors.Oops("The ID token is not valid yet.", nil)
+	}
+
+	if claims.IssuedAt != 0 && now.Add(ClockSkew).Before(time.Unix(claims.IssuedAt, 0)) {
+		return errors.Oops("The ID token is issued in the future.", nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package openidconnect
+
+import (
+	"net/http"
+	"time"
+
+	oauth2 "github.com/x1n13y84issmd42/oasis/src/api/security/OAuth2"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Security implements the 'openIdConnect' security type.
+// It discovers the token endpoint of the provider and obtains tokens
+// the same way as the OAuth2 security does. The ID tokens issued
+// along with the access tokens are verified before the latter are used.
+type Security struct {
+	*oauth2.Security
+
+	// DiscoveryURL is the location of the provider configuration.
+	DiscoveryURL string
+}
+
+// New creates a new OpenID Connect security.
+func New(name string, discoveryURL string, scopes []string, creds oauth2.Credentials, logger contract.Logger) *Security {
+	// Providers issue ID tokens for the "openid" scope only.
+	hasOpenID := false
+	for _, scope := range scopes {
+		hasOpenID = hasOpenID || scope == "openid"
+	}
+
+	if !hasOpenID {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	return &Security{
+		Security:     oauth2.New(name, oauth2.Flows{}, scopes, creds, logger),
+		DiscoveryURL: discoveryURL,
+	}
+}
+
+// Enrich discovers the provider, obtains an access token and adds it
+// to the Authorization request header.
+func (sec *Security) Enrich(req *http.Request, log contract.Logger) {
+	if !sec.HasCredentials() {
+		log.UsingSecurity(sec)
+		log.SecurityHasNoData(sec)
+		return
+	}
+
+	err := sec.Discover(log)
+	if err != nil {
+		log.UsingSecurity(sec)
+		log.Error(err)
+		return
+	}
+
+	sec.Security.Enrich(req, log)
+}
+
+// Discover fetches the provider configuration and sets up the token endpoints
+// of the grants the provider supports.
+func (sec *Security) Discover(log contract.Logger) error {
+	if sec.Verify != nil {
+		return nil
+	}
+
+	log.DiscoveringOpenIDConnect(sec.Name, sec.DiscoveryURL)
+
+	config, err := Discovered.Configuration(sec.DiscoveryURL)
+	if err != nil {
+		return err
+	}
+
+	sec.Flows = oauth2.Flows{}
+
+	if config.Supports("password") {
+		sec.Flows.Password = config.TokenEndpoint
+	}
+
+	if config.Supports("client_credentials") {
+		sec.Flows.ClientCredentials = config.TokenEndpoint
+	}
+
+	if config.Supports("refresh_token") {
+		sec.Flows.Refresh = config.TokenEndpoint
+	}
+
+	sec.Verify = func(token *oauth2.Token) error {
+		return sec.VerifyIDToken(config, token)
+	}
+
+	return nil
+}
+
+// VerifyIDToken verifies the signature & the claims of the ID token
+// issued along with the access token. Some grants, like the client credentials one,
+// don't issue ID tokens, so their absence is fine.
+func (sec *Security) VerifyIDToken(config *Configuration, token *oauth2.Token) error {
+	if token.IDToken == "" {
+		return nil
+	}
+
+	idToken, err := ParseIDToken(token.IDToken)
+	if err != nil {
+		return err
+	}
+
+	var keys *JWKS
+	if config.JWKSURI != "" {
+		keys, err = Discovered.Keys(config.JWKSURI)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = idToken.VerifySignature(keys, sec.ClientSecret())
+	if err != nil {
+		return err
+	}
+
+	return idToken.VerifyClaims(config.Issuer, sec.ClientID(), time.Now())
+}
@@ -0,0 +1,208 @@
+package openidconnect_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	oauth2 "github.com/x1n13y84issmd42/oasis/src/api/security/OAuth2"
+	oidc "github.com/x1n13y84issmd42/oasis/src/api/security/OpenIDConnect"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signed(alg string, kid string, claims map[string]interface{}, sign func(digest []byte, signed string) []byte) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid})
+	payload, _ := json.Marshal(claims)
+	s := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(s))
+
+	return s + "." + b64(sign(digest[:], s))
+}
+
+func Test_OpenIDConnect(T *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	rs256 := func(key *rsa.PrivateKey) func([]byte, string) []byte {
+		return func(digest []byte, _ string) []byte {
+			sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+			return sig
+		}
+	}
+
+	var server *httptest.Server
+	idToken := ""
+	grants := []string{}
+	grantTypes := []string{}
+
+	claims := func(aud string, exp time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"iss": server.URL,
+			"sub": "user",
+			"aud": aud,
+			"exp": exp.Unix(),
+			"iat": time.Now().Unix(),
+		}
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch req.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issuer":                server.URL,
+				"token_endpoint":        "/token",
+				"jwks_uri":              server.URL + "/jwks",
+				"grant_types_supported": grantTypes,
+			})
+
+		case "/jwks":
+			json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "1",
+				"use": "sig",
+				"n":   b64(key.N.Bytes()),
+				"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+			}}})
+
+		case "/token":
+			req.ParseForm()
+			grants = append(grants, req.PostForm.Get("grant_type")+" "+req.PostForm.Get("scope"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "id_token": idToken, "expires_in": 3600})
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	discoveryURL := server.URL + "/.well-known/openid-configuration"
+
+	reset := func(token string, supported ...string) {
+		oauth2.Tokens = oauth2.NewTokenCache()
+		oidc.Discovered = oidc.NewDiscoveryCache()
+		idToken = token
+		grants = []string{}
+		grantTypes = supported
+	}
+
+	authorization := func(sec *oidc.Security) string {
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		sec.Enrich(req, log.NewPlain(0))
+
+		return req.Header.Get("Authorization")
+	}
+
+	T.Run("Password", func(T *testing.T) {
+		reset(signed("RS256", "1", claims("app", time.Now().Add(time.Hour)), rs256(key)))
+
+		sec := oidc.New("oidc", discoveryURL, []string{"pets"}, oauth2.Credentials{ClientID: "app", Username: "user", Password: "secret"}, log.NewPlain(0))
+
+		assert.Equal(T, "Bearer access", authorization(sec))
+		assert.Equal(T, []string{"password openid pets"}, grants)
+	})
+
+	T.Run("Client credentials", func(T *testing.T) {
+		reset("", "client_credentials")
+
+		sec := oidc.New("oidc", discoveryURL, []string{"openid"}, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t", Username: "user"}, log.NewPlain(0))
+
+		assert.Equal(T, "Bearer access", authorization(sec))
+		assert.Equal(T, []string{"client_credentials openid"}, grants)
+	})
+
+	T.Run("Invalid ID tokens", func(T *testing.T) {
+		tokens := map[string]string{
+			"Signature": signed("RS256", "1", claims("app", time.Now().Add(time.Hour)), rs256(otherKey)),
+			"Audience":  signed("RS256", "1", claims("other", time.Now().Add(time.Hour)), rs256(key)),
+			"Expired":   signed("RS256", "1", claims("app", time.Now().Add(-time.Hour)), rs256(key)),
+			"None":      signed("none", "", claims("app", time.Now().Add(time.Hour)), func([]byte, string) []byte { return nil }),
+			"Garbage":   "not.a.jwt",
+		}
+
+		for name, token := range tokens {
+			reset(token)
+
+			sec := oidc.New("oidc", discoveryURL, nil, oauth2.Credentials{ClientID: "app", Username: "user", Password: "secret"}, log.NewPlain(0))
+
+			assert.Equal(T, "", authorization(sec), name)
+		}
+	})
+
+	T.Run("Local stand-in", func(T *testing.T) {
+		reset(signed("HS256", "", claims("app", time.Now().Add(time.Hour)), func(_ []byte, s string) []byte {
+			mac := hmac.New(sha256.New, []byte("s3cr3t"))
+			mac.Write([]byte(s))
+			return mac.Sum(nil)
+		}))
+
+		dir, _ := ioutil.TempDir("", "oasis")
+		defer os.RemoveAll(dir)
+
+		config, _ := json.Marshal(map[string]string{
+			"issuer":         server.URL,
+			"token_endpoint": server.URL + "/token",
+			"jwks_uri":       "jwks.json",
+		})
+
+		ioutil.WriteFile(filepath.Join(dir, "openid-configuration.json"), config, 0644)
+		ioutil.WriteFile(filepath.Join(dir, "jwks.json"), []byte(`{"keys": []}`), 0644)
+
+		location := oidc.Resolve(filepath.Join(dir, "spec.yaml"), "openid-configuration.json")
+		sec := oidc.New("oidc", location, nil, oauth2.Credentials{ClientID: "app", ClientSecret: "s3cr3t", Username: "user", Password: "secret"}, log.NewPlain(0))
+
+		assert.Equal(T, "Bearer access", authorization(sec))
+
+		keys, err := oidc.Discovered.Keys(filepath.Join(dir, "jwks.json"))
+		assert.Nil(T, err)
+		assert.Empty(T, keys.Keys)
+	})
+
+	T.Run("EC keys", func(T *testing.T) {
+		ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+		raw := signed("ES256", "ec", claims("app", time.Now().Add(time.Hour)), func(digest []byte, _ string) []byte {
+			r, s, _ := ecdsa.Sign(rand.Reader, ecKey, digest)
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			return sig
+		})
+
+		keys := &oidc.JWKS{Keys: []oidc.JWK{{
+			Kty: "EC",
+			Kid: "ec",
+			Crv: "P-256",
+			X:   b64(ecKey.X.FillBytes(make([]byte, 32))),
+			Y:   b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		}}}
+
+		token, err := oidc.ParseIDToken(raw)
+		assert.Nil(T, err)
+		assert.Nil(T, token.VerifySignature(keys, ""))
+		assert.Nil(T, token.VerifyClaims(server.URL, "app", time.Now()))
+
+		token.Signature[0] ^= 1
+		assert.NotNil(T, token.VerifySignature(keys, ""))
+	})
+}
@@ -0,0 +1,27 @@
+package contract
+
+// ConformanceEntry is the outcome of checking a recorded HTTP exchange
+// against a spec. Operation is empty when no spec operation matches it.
+// Aborted exchanges (status 0, like blocked or cancelled requests) have
+// no response to check, they are neither conformant nor violating.
+type ConformanceEntry struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Operation  string      `json:"operation,omitempty"`
+	Conformant bool        `json:"conformant"`
+	Aborted    bool        `json:"aborted,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// ConformanceReport tells how well the recorded traffic conforms to a spec.
+// Total doesn't include the aborted exchanges, those are counted in Aborted.
+type ConformanceReport struct {
+	Spec       string             `json:"spec"`
+	Title      string             `json:"title"`
+	Total      int                `json:"total"`
+	Conformant int                `json:"conformant"`
+	Aborted    int                `json:"aborted"`
+	Percent    float64            `json:"percent"`
+	Entries    []ConformanceEntry `json:"entries"`
+}
@@ -0,0 +1,42 @@
+package contract
+
+// CoverageItem is something in a spec which API tests may or may not exercise,
+// like a response, a parameter or an enum value.
+type CoverageItem struct {
+	Name    string `json:"name"`
+	Covered bool   `json:"covered"`
+}
+
+// OperationCoverage describes how well an operation is covered by tests.
+// Responses are like "200 application/json", parameters are like "query limit",
+// branches are oneOf/anyOf alternatives & enum values, like
+// "response 200 application/json .status enum sold".
+type OperationCoverage struct {
+	Operation  string         `json:"operation"`
+	ID         string         `json:"id,omitempty"`
+	Covered    bool           `json:"covered"`
+	Responses  []CoverageItem `json:"responses"`
+	Parameters []CoverageItem `json:"parameters"`
+	Branches   []CoverageItem `json:"branches"`
+}
+
+// Items returns all the coverage items of an operation,
+// including the operation itself.
+func (opc OperationCoverage) Items() []CoverageItem {
+	items := []CoverageItem{{Name: opc.Operation, Covered: opc.Covered}}
+	items = append(items, opc.Responses...)
+	items = append(items, opc.Parameters...)
+	items = append(items, opc.Branches...)
+
+	return items
+}
+
+// CoverageReport describes how well a spec is covered by tests.
+type CoverageReport struct {
+	Spec       string              `json:"spec"`
+	Title      string              `json:"title"`
+	Covered    int                 `json:"covered"`
+	Total      int                 `json:"total"`
+	Percent    float64             `json:"percent"`
+	Operations []OperationCoverage `json:"operations"`
+}
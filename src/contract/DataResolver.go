@@ -12,4 +12,12 @@ type DataResolver interface {
 	Host(hostHint string) ParameterSource
 	Security(secName string) Security
 	Response(status int64, CT string) Validator
+
+	// RequestViolations validates an outgoing request
+	// from the result before it is sent.
+	RequestViolations(result *OperationResult) []Violation
+
+	// ResponseProperties returns the names of the top-level properties
+	// of the documented response bodies.
+	ResponseProperties() []string
 }
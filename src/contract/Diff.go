@@ -0,0 +1,12 @@
+package contract
+
+// SpecChange is a difference between two versions of a spec.
+// Operation is like "GET /pets/{id}", it's empty for spec-wide changes.
+// Location is a place within the operation, like "response 200 application/json .name".
+// Breaking changes are the ones which may break existing API clients.
+type SpecChange struct {
+	Operation string `json:"operation,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Message   string `json:"message"`
+	Breaking  bool   `json:"breaking"`
+}
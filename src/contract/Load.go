@@ -0,0 +1,19 @@
+package contract
+
+import "time"
+
+// LoadReport is a summary of a load test.
+// Statuses counts the received responses by their status code,
+// Failures counts the failed operations in the same manner.
+// Status 0 means there was no response at all.
+type LoadReport struct {
+	Requests       int
+	Duration       time.Duration
+	Throughput     float64
+	Statuses       map[int]int
+	Failures       map[int]int
+	SchemaFailures int
+	P50            time.Duration
+	P90            time.Duration
+	P99            time.Duration
+}
@@ -2,6 +2,7 @@ package contract
 
 import (
 	"strings"
+	"time"
 
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -27,18 +28,28 @@ type Logger interface {
 
 	UsingSecurity(sec Security)
 	SecurityHasNoData(sec Security)
+	ObtainingToken(secName string, grant string, URL string)
+	DiscoveringOpenIDConnect(secName string, URL string)
 
 	Requesting(method string, url string)
+	InvalidRequest(violations []Violation, strict bool)
+	RequestTiming(timing RequestTiming)
 
 	UsingParameterExample(paramName string, in string, container string, value string)
 
 	Expecting(what string, v string)
 	ExpectingProperty(what string, v string)
+	ExpectingHeader(what string, v string)
 
 	HeaderHasNoValue(hdr string)
 	ResponseHasWrongStatus(expectedStatus int, actualStatus int)
 	ResponseHasWrongContentType(expectedCT string, actualCT string)
 	ResponseHasWrongPropertyValue(propName string, expected string, actual string)
+	ResponseHasWrongHeaderValue(hdr string, expected string, actual string)
+	ResponseBodyMismatch(pointer string, expected string, actual string)
+	ResponseSnapshotMismatch(pointer string, expected string, actual string)
+	SnapshotSaved(path string)
+	ResponseIsTooSlow(maxTime time.Duration, actualTime time.Duration)
 
 	OperationOK()
 	OperationFail()
@@ -47,6 +58,31 @@ type Logger interface {
 	SchemaFail(schemaName string, errors []gojsonschema.ResultError)
 
 	ScriptExecutionStart(node string)
+	ForEachItem(i int64, total int64)
+	DatasetRow(i int64, total int64, row map[string]string)
+	DatasetReport(results []bool)
+	ShadowedProperty(ref string, property string, bodyRef string)
+	ConditionFalse(condition string)
+	DependencySkipped(opRefID string)
+	UsingFallback(opRefID string)
+	OperationSkipped()
+	Polling(condition string, attempt int64, delay time.Duration)
+	PollingGaveUp(condition string, attempts int64)
+
+	LoadStarting(rps int64, duration time.Duration, workers int64)
+	LoadReport(report LoadReport)
+
+	DiffingSpecs(oldPath string, newPath string)
+	SpecDiffReport(changes []SpecChange)
+	CoverageReport(reports []CoverageReport)
+
+	MockServing(pi ProjectInfo, address string)
+	ProxyServing(pi ProjectInfo, address string, target string)
+	ServedRequest(method string, URL string, status int)
+	Violation(location string, msg string)
+
+	VerifyingTraffic(path string, pi ProjectInfo)
+	ConformanceReport(report ConformanceReport)
 
 	XError(err error, style LogStyle, tab TabFn)
 
@@ -67,12 +103,14 @@ type LogStyle interface {
 	Op(...interface{}) string
 	OK(...interface{}) string
 	Failure(...interface{}) string
+	Skipped(...interface{}) string
 	Success(...interface{}) string
 	Error(...interface{}) string
 	ID(...interface{}) string
 	ValueExpected(...interface{}) string
 	ValueActual(...interface{}) string
 	Value(...interface{}) string
+	Duration(...interface{}) string
 }
 
 // TabFn produces indentation when printing nested errors.
@@ -2,15 +2,31 @@ package contract
 
 import (
 	"net/http"
+	"time"
 )
 
+// RequestTiming is a breakdown of the time spent on various phases
+// of an HTTP request. Phases which didn't happen (f.e. TLS for plain HTTP
+// or DNS for a reused connection) are left zero.
+type RequestTiming struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	TTFB    time.Duration
+	Total   time.Duration
+}
+
 // OperationResult describes the outcome of an operation test.
 // It is bo te used as a possible source of data for subsequent tests.
 type OperationResult struct {
-	Success       bool
-	HTTPRequest   *http.Request
-	HTTPResponse  *http.Response
-	ResponseBytes []byte
+	Success        bool
+	Skipped        bool
+	HTTPRequest    *http.Request
+	HTTPResponse   *http.Response
+	RequestBytes   []byte
+	ResponseBytes  []byte
+	PathParameters map[string]string
+	Timing         RequestTiming
 }
 
 // And creates a new OperationResult instance with the Success field assigned
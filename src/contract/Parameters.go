@@ -17,6 +17,7 @@ type StringParameters interface {
 	IEntityTrait
 	Set
 	String() string
+	Values() map[string]string
 }
 
 // RequestEnrichmentParameters is used to enrich http.Request instances
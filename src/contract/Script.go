@@ -8,6 +8,9 @@ type ScriptSecurity struct {
 	Token    string `yaml:"token"`
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
+
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
 }
 
 // SecurityAccess contains parameter access functions
@@ -18,10 +21,14 @@ type SecurityAccess struct {
 	Token    ParameterAccess
 	Username ParameterAccess
 	Password ParameterAccess
+
+	ClientID     ParameterAccess
+	ClientSecret ParameterAccess
 }
 
 // Script is an interface to scenario scripts.
 type Script interface {
 	GetExecutionGraph() gcontract.Graph
 	GetSecurity(name string) *SecurityAccess
+	GetSpecs() map[string]Spec
 }
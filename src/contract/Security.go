@@ -15,4 +15,6 @@ type Security interface {
 	SetToken(v ParameterAccess)
 	SetUsername(v ParameterAccess)
 	SetPassword(v ParameterAccess)
+	SetClientID(v ParameterAccess)
+	SetClientSecret(v ParameterAccess)
 }
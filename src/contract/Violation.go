@@ -0,0 +1,9 @@
+package contract
+
+// Violation is a discrepancy between an HTTP message and it's spec.
+// Location is a place within the message, like "query limit"
+// or "request body application/json /owner/name".
+type Violation struct {
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
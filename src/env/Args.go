@@ -2,9 +2,12 @@ package env
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/ssp"
 )
@@ -108,8 +111,49 @@ type ArgsUse struct {
 
 // ArgsExpect is what goes after the "expect" command line argument.
 type ArgsExpect struct {
-	CT     string
-	Status int64
+	CT       string
+	Status   int64
+	MaxTime  time.Duration
+	Snapshot ArgsSnapshot
+}
+
+// ArgsSnapshot is what goes after the "expect snapshot" command line arguments.
+type ArgsSnapshot struct {
+	Enabled bool
+	Ignore  []string
+}
+
+// ArgsLoad is what goes after the "load" command line argument.
+type ArgsLoad struct {
+	Enabled  bool
+	RPS      int64
+	Duration time.Duration
+	Workers  int64
+}
+
+// ArgsDiff is what goes after the "diff" command line argument.
+type ArgsDiff struct {
+	Old string
+	New string
+}
+
+// ArgsCoverage is what goes after the "coverage" command line argument.
+type ArgsCoverage struct {
+	Enabled bool
+	Path    string
+}
+
+// ArgsMock is what goes after the "mock" command line argument.
+type ArgsMock struct {
+	Spec    string
+	Address string
+}
+
+// ArgsProxy is what goes after the "proxy" command line argument.
+type ArgsProxy struct {
+	Spec    string
+	Address string
+	Target  string
 }
 
 // Args is a program arguments.
@@ -120,13 +164,69 @@ type Args struct {
 	Ops      []string
 	Use      ArgsUse
 	Expect   ArgsExpect
+	Load     ArgsLoad
+	Diff     ArgsDiff
+	Coverage ArgsCoverage
+	Mock     ArgsMock
+	Proxy    ArgsProxy
+	Verify   string
+	Format   string
+	Vars     map[string]string
+	Seed     *int64
+	Update   bool
+	Strict   bool
+	Record   string
+	Replay   string
 	LogLevel int64
 	LogStyle string
+
+	// Errors are the argument values which cannot be parsed.
+	// They are reported when the logger is set up.
+	Errors []error
+}
+
+// Flag creates an expression which matches the f string
+// and sets the b value to true when matched.
+func Flag(f string, b *bool) *ssp.SSP {
+	p := ssp.New()
+	p.Expressions = append(p.Expressions, func(cursor *ssp.Cursor) bool {
+		if cursor.Get() == f {
+			*b = true
+			cursor.Shift(1)
+			return true
+		}
+
+		return false
+	})
+
+	return p
+}
+
+// JoinExpressions joins back the comma-separated values which were split
+// inside function calls, like "n=$randomInt(1,100)".
+func JoinExpressions(values []string) []string {
+	res := []string{}
+	depth := 0
+
+	for _, v := range values {
+		if depth > 0 {
+			res[len(res)-1] += "," + v
+		} else {
+			res = append(res, v)
+		}
+
+		depth += strings.Count(v, "(") - strings.Count(v, ")")
+	}
+
+	return res
 }
 
 // ParseArgs parses command line arguments into the args struct.
 func ParseArgs(args *Args) {
-	expExecute := ssp.String("execute").CaptureString(&args.Script)
+	expExecute := ssp.OneOf(
+		ssp.String("execute").String("script").CaptureString(&args.Script),
+		ssp.String("execute").CaptureString(&args.Script),
+	)
 	expFrom := ssp.String("from").CaptureString(&args.Spec)
 	expTest := ssp.String("test").CaptureStringSlice(&args.Ops)
 	expHost := ssp.String("@").CaptureString(&args.Host)
@@ -134,8 +234,8 @@ func ParseArgs(args *Args) {
 	args.Use.PathParameters = ParameterMapPath{}
 
 	hPathParams := func(params []string) {
-		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+		for _, pp := range JoinExpressions(params) {
+			pps := strings.SplitN(pp, "=", 2)
 			args.Use.PathParameters[pps[0]] = pps[1]
 		}
 	}
@@ -146,8 +246,8 @@ func ParseArgs(args *Args) {
 	args.Use.Headers["Content-Type"] = []string{"application/x-www-form-urlencoded"}
 
 	hQueryParams := func(params []string) {
-		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+		for _, pp := range JoinExpressions(params) {
+			pps := strings.SplitN(pp, "=", 2)
 			if args.Use.Query[pps[0]] == nil {
 				args.Use.Query[pps[0]] = []string{}
 			}
@@ -158,8 +258,8 @@ func ParseArgs(args *Args) {
 	args.Use.Body = ParameterMapBody{}
 
 	hBodyProps := func(params []string) {
-		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+		for _, pp := range JoinExpressions(params) {
+			pps := strings.SplitN(pp, "=", 2)
 			args.Use.Body[pps[0]] = pps[1]
 		}
 	}
@@ -172,10 +272,101 @@ func ParseArgs(args *Args) {
 		ssp.Strings("body", "props").HandleStringSlice(hBodyProps),
 	), 0, 4)
 
+	hMaxTime := func(v string) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			args.Errors = append(args.Errors, errors.Oops("Cannot parse the maximum response time '"+v+"'.", err))
+			return
+		}
+
+		args.Expect.MaxTime = d
+	}
+
 	expExpect := ssp.String("expect").Repeat(ssp.OneOf(
 		ssp.String("CT").CaptureString(&args.Expect.CT),
 		ssp.String("status").CaptureInt64(&args.Expect.Status),
+		ssp.String("time").OneOf([]*ssp.SSP{
+			ssp.String("<"),
+			ssp.String("under"),
+		}).HandleString(hMaxTime),
+		Flag("snapshot", &args.Expect.Snapshot.Enabled).Repeat(
+			ssp.String("ignoring").CaptureStringSlice(&args.Expect.Snapshot.Ignore),
+			0, 1,
+		),
+	), 0, 4)
+
+	hLoadOps := func(ops []string) {
+		args.Load.Enabled = true
+		args.Ops = ops
+	}
+
+	hLoadDuration := func(v string) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			args.Errors = append(args.Errors, errors.Oops("Cannot parse the load test duration '"+v+"'.", err))
+			return
+		}
+
+		args.Load.Duration = d
+	}
+
+	expLoadSettings := ssp.OneOf(
+		ssp.String("at").CaptureInt64(&args.Load.RPS).String("rps"),
+		ssp.String("for").HandleString(hLoadDuration),
+		ssp.String("with").CaptureInt64(&args.Load.Workers).String("workers"),
+	)
+
+	// Scripts have no operations to list, hence the two forms.
+	expLoad := ssp.OneOf(
+		Flag("load", &args.Load.Enabled).Repeat(expLoadSettings, 1, 3),
+		ssp.String("load").HandleStringSlice(hLoadOps).Repeat(expLoadSettings, 0, 3),
+	)
+
+	args.Vars = map[string]string{}
+
+	hVars := func(vars []string) {
+		for _, v := range vars {
+			vs := strings.SplitN(v, "=", 2)
+			if len(vs) != 2 {
+				args.Errors = append(args.Errors, errors.Oops("Cannot parse the variable '"+v+"', it should look like 'NAME=VALUE'.", nil))
+				continue
+			}
+
+			args.Vars[vs[0]] = vs[1]
+		}
+	}
+
+	hSeed := func(v string) {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			args.Errors = append(args.Errors, errors.Oops("Cannot parse the seed '"+v+"'.", err))
+			return
+		}
+
+		args.Seed = &seed
+	}
+
+	expWith := ssp.OneOf(
+		ssp.Strings("with", "vars").HandleStringSlice(hVars),
+		ssp.Strings("with", "seed").HandleString(hSeed),
+	)
+
+	expDiff := ssp.String("diff").CaptureString(&args.Diff.Old).CaptureString(&args.Diff.New)
+	expFormat := ssp.String("as").CaptureString(&args.Format)
+	expMock := ssp.String("mock").CaptureString(&args.Mock.Spec).Repeat(
+		ssp.String("on").CaptureString(&args.Mock.Address),
+		0, 1,
+	)
+	// The proxy clause takes it's own "from" & "to", so they aren't
+	// mistaken for the spec ones.
+	expProxy := ssp.String("proxy").CaptureString(&args.Proxy.Spec).Repeat(ssp.OneOf(
+		ssp.String("from").CaptureString(&args.Proxy.Address),
+		ssp.String("to").CaptureString(&args.Proxy.Target),
 	), 0, 2)
+	expCoverage := Flag("coverage", &args.Coverage.Enabled).Repeat(
+		ssp.String("to").CaptureString(&args.Coverage.Path),
+		0, 1,
+	)
 
 	expLogLevel := ssp.Strings("at", "level").CaptureInt64(&args.LogLevel)
 	expLogStyle := ssp.String("in").CaptureString(&args.LogStyle).String("style")
@@ -194,7 +385,19 @@ func ParseArgs(args *Args) {
 		expExpect,
 		expHost,
 		expLog,
-	), 1, 6).Parse(os.Args[1:])
+		expLoad,
+		expWith,
+		Flag("--update-snapshots", &args.Update),
+		ssp.String("strict").Repeat(Flag("requests", &args.Strict), 1, 1),
+		ssp.String("--record").CaptureString(&args.Record),
+		ssp.String("--replay").CaptureString(&args.Replay),
+		expDiff,
+		expFormat,
+		expCoverage,
+		expMock,
+		expProxy,
+		ssp.String("verify").CaptureString(&args.Verify),
+	), 1, 19).Parse(os.Args[1:])
 	//    ^^^ UPDATE ME EVERY TIME YOU ADD ARGUMENTS
 
 	// fmt.Printf("Args: %#v\n", args)
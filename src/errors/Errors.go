@@ -324,3 +324,23 @@ func NotAn(whatShouldBe string, whatIs interface{}, cause error) ErrNotAn {
 		whatIs:       whatIs,
 	}
 }
+
+// ErrSelectorSyntax happens when a reference selector cannot be parsed.
+type ErrSelectorSyntax struct {
+	Base
+	Selector string
+	Pos      int
+}
+
+func (err ErrSelectorSyntax) Error() string {
+	return fmt.Sprintf("Cannot parse the selector '%s' at position %d: %s", err.Selector, err.Pos, err.Details)
+}
+
+// SelectorSyntax creates a new ErrSelectorSyntax error instance.
+func SelectorSyntax(selector string, pos int, details string, cause error) ErrSelectorSyntax {
+	return ErrSelectorSyntax{
+		Base:     NewBase(cause, details),
+		Selector: selector,
+		Pos:      pos,
+	}
+}
@@ -37,6 +37,11 @@ func (log Festive) Failure(args ...interface{}) string {
 	return color.New(color.FgLightWhite, color.BgRed).Sprint(args...) + "\x1b[K"
 }
 
+// Skipped marks up the skipped operation testing result.
+func (log Festive) Skipped(args ...interface{}) string {
+	return color.New(color.FgBlack, color.BgYellow).Sprint(args...) + "\x1b[K"
+}
+
 // Success marks up the successful testing of operation properties.
 func (log Festive) Success(args ...interface{}) string {
 	return color.New(color.FgGreen).Sprint(args...) + "\x1b[K"
@@ -66,3 +71,8 @@ func (log Festive) ValueActual(args ...interface{}) string {
 func (log Festive) Value(args ...interface{}) string {
 	return color.New(48, 5, 240, 38, 5, 255).Sprint(args...) + "\x1b[K"
 }
+
+// Duration marks up time durations.
+func (log Festive) Duration(args ...interface{}) string {
+	return color.New(color.FgLightMagenta).Sprint(args...) + "\x1b[K"
+}
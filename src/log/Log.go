@@ -3,7 +3,9 @@ package log
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
@@ -172,11 +174,46 @@ func (log *Log) SecurityHasNoData(sec contract.Security) {
 	log.Println(3, "\tThe security %s contains no data to use in request.", log.Style.ID(sec.GetName()))
 }
 
+// ObtainingToken informs about an access token being requested for a security.
+func (log *Log) ObtainingToken(secName string, grant string, URL string) {
+	log.Println(3, "\tObtaining a %s token for the %s security from %s.", log.Style.Value(grant), log.Style.ID(secName), log.Style.URL(URL))
+}
+
+// DiscoveringOpenIDConnect informs about an OpenID Connect provider configuration being fetched.
+func (log *Log) DiscoveringOpenIDConnect(secName string, URL string) {
+	log.Println(3, "\tDiscovering the OpenID Connect provider of the %s security at %s.", log.Style.ID(secName), log.Style.URL(URL))
+}
+
 // Requesting informs about an HTTP request being performed.
 func (log *Log) Requesting(method string, URL string) {
 	log.Println(2, "\tRequesting %s @ %s", log.Style.Method(method), log.Style.URL(URL))
 }
 
+// InvalidRequest informs that the request doesn't conform to the spec.
+// Strict requests aren't sent.
+func (log *Log) InvalidRequest(violations []contract.Violation, strict bool) {
+	if strict {
+		log.Println(1, "\t%s", log.Style.Error("The request doesn't conform to the spec, not sending it:"))
+	} else {
+		log.Println(1, "\tThe request doesn't conform to the spec:")
+	}
+
+	for _, v := range violations {
+		log.Println(1, "\t\t%s: %s", log.Style.ID(v.Location), v.Message)
+	}
+}
+
+// RequestTiming informs about time spent on the phases of an HTTP request.
+func (log *Log) RequestTiming(timing contract.RequestTiming) {
+	log.Println(2, "\tTook %s (DNS %s, connect %s, TLS %s, first byte %s).",
+		log.Style.Duration(timing.Total.Round(time.Microsecond)),
+		log.Style.Duration(timing.DNS.Round(time.Microsecond)),
+		log.Style.Duration(timing.Connect.Round(time.Microsecond)),
+		log.Style.Duration(timing.TLS.Round(time.Microsecond)),
+		log.Style.Duration(timing.TTFB.Round(time.Microsecond)),
+	)
+}
+
 // UsingParameterExample informs that a parameter example being used.
 func (log *Log) UsingParameterExample(paramName string, in string, container string, value string) {
 	log.Println(5, "\tUsing the %s parameter %s %s (from %s).", in, log.Style.ID(paramName), log.Style.Value(value), container)
@@ -192,6 +229,11 @@ func (log *Log) ExpectingProperty(what string, v string) {
 	log.Println(5, "\tExpecting %s body property %s.", log.Style.ID(what), log.Style.Value(v))
 }
 
+// ExpectingHeader informs about an expected response header value.
+func (log *Log) ExpectingHeader(what string, v string) {
+	log.Println(5, "\tExpecting %s header %s.", log.Style.ID(what), log.Style.Value(v))
+}
+
 // HeaderHasNoValue informs that a required response header has no data.
 func (log *Log) HeaderHasNoValue(hdr string) {
 	log.Println(1, "\tHeader \"%s\" is required but is not present.", hdr)
@@ -226,7 +268,7 @@ func (log *Log) ResponseHasWrongContentType(expectedCT string, actualCT string)
 func (log *Log) ResponseHasWrongPropertyValue(propName string, expected string, actual string) {
 	m := strings.Join([]string{
 		"\t",
-		"Expected the %s property to equal %s ",
+		"Expected the %s property to be %s ",
 		"but got %s",
 		".",
 	}, "")
@@ -234,6 +276,46 @@ func (log *Log) ResponseHasWrongPropertyValue(propName string, expected string,
 	log.Println(2, m, log.Style.ID(propName), log.Style.ValueExpected(expected), log.Style.ValueActual(actual))
 }
 
+// ResponseHasWrongHeaderValue informs that the received response has wrong/unexpected header value.
+func (log *Log) ResponseHasWrongHeaderValue(hdr string, expected string, actual string) {
+	m := strings.Join([]string{
+		"\t",
+		"Expected the %s header to be %s ",
+		"but got %s",
+		".",
+	}, "")
+
+	log.Println(2, m, log.Style.ID(hdr), log.Style.ValueExpected(expected), log.Style.ValueActual(actual))
+}
+
+// ResponseBodyMismatch informs that the received response body differs from the expected document.
+func (log *Log) ResponseBodyMismatch(pointer string, expected string, actual string) {
+	log.Println(2, "\tExpected %s at %s but got %s.", log.Style.ValueExpected(expected), log.Style.ID(pointer), log.Style.ValueActual(actual))
+}
+
+// ResponseSnapshotMismatch informs that the received response differs from the snapshot.
+func (log *Log) ResponseSnapshotMismatch(pointer string, expected string, actual string) {
+	log.Println(2, "\tThe snapshot has %s at %s but got %s.", log.Style.ValueExpected(expected), log.Style.ID(pointer), log.Style.ValueActual(actual))
+}
+
+// SnapshotSaved informs that the response has been recorded to a snapshot file.
+func (log *Log) SnapshotSaved(path string) {
+	log.Println(2, "\tSaved the response snapshot to %s.", log.Style.URL(path))
+}
+
+// ResponseIsTooSlow informs that the response took longer than expected.
+func (log *Log) ResponseIsTooSlow(maxTime time.Duration, actualTime time.Duration) {
+	m := strings.Join([]string{
+		"\t",
+		"Expected the ",
+		log.Style.ID("response time"),
+		" to be under %s, but it took %s",
+		".",
+	}, "")
+
+	log.Println(2, m, log.Style.ValueExpected(maxTime), log.Style.ValueActual(actualTime))
+}
+
 // TestingOperation informs about an operation being tested.
 func (log *Log) TestingOperation(op contract.Operation) {
 	log.Print(1, "Testing the %s operation... ", log.Style.Op(op.Name()))
@@ -254,6 +336,13 @@ func (log *Log) OperationFail() {
 	log.Print(2, "\n")
 }
 
+// OperationSkipped informs that the operation has been skipped.
+func (log *Log) OperationSkipped() {
+	log.Print(2, "\t")
+	log.Println(1, "%s", log.Style.Skipped("SKIPPED"))
+	log.Print(2, "\n")
+}
+
 // SchemaTesting informs about a value being tested againt some JSON schema.
 func (log *Log) SchemaTesting(schema *api.Schema, data interface{}) {
 	datas := log.Style.Value(fmt.Sprintf("%#v", data))
@@ -279,6 +368,305 @@ func (log *Log) ScriptExecutionStart(node string) {
 	log.Println(5, "Execution starts from the node %s.\n", log.Style.Op(node))
 }
 
+// ForEachItem informs about the iteration of a 'forEach' operation.
+// Iterations are zero-based, just like the references to them.
+func (log *Log) ForEachItem(i int64, total int64) {
+	log.Println(1, "\tItem %s of %s:", log.Style.Value(fmt.Sprintf("#%d", i)), log.Style.Value(total))
+}
+
+// DatasetRow informs about the dataset row a script is executed with.
+func (log *Log) DatasetRow(i int64, total int64, row map[string]string) {
+	columns := []string{}
+	for column := range row {
+		columns = append(columns, column)
+	}
+
+	sort.Strings(columns)
+
+	values := []string{}
+	for _, column := range columns {
+		values = append(values, log.Style.ID(column)+"="+log.Style.Value(row[column]))
+	}
+
+	log.Println(1, "Row %s of %s: %s\n", log.Style.Value(fmt.Sprintf("#%d", i)), log.Style.Value(total), strings.Join(values, ", "))
+}
+
+// DatasetReport prints the outcomes of the script execution for every dataset row.
+func (log *Log) DatasetReport(results []bool) {
+	failures := 0
+
+	log.Println(1, "")
+	for i, success := range results {
+		if success {
+			log.Println(1, "Row %s: %s", log.Style.Value(fmt.Sprintf("#%d", i)), log.Style.OK("SUCCESS"))
+		} else {
+			failures++
+			log.Println(1, "Row %s: %s", log.Style.Value(fmt.Sprintf("#%d", i)), log.Style.Failure("FAILURE"))
+		}
+	}
+
+	log.Println(1, "Rows: %s passed, %s failed.", log.Style.Value(len(results)-failures), log.Style.Value(failures))
+}
+
+// ShadowedProperty warns that a reference points at a part of the response
+// rather than at the response body property with the same name.
+func (log *Log) ShadowedProperty(ref string, property string, bodyRef string) {
+	log.Println(1, "\tThe reference %s points at the response %s rather than at the body property '%s', use %s for the property.", log.Style.Value(ref), property, property, log.Style.Value(bodyRef))
+}
+
+// ConditionFalse informs that the condition of an operation doesn't hold.
+func (log *Log) ConditionFalse(condition string) {
+	log.Println(2, "\tThe condition %s doesn't hold.", log.Style.Value(condition))
+}
+
+// DependencySkipped informs that an operation has been skipped
+// because some operation it depends on has been skipped.
+func (log *Log) DependencySkipped(opRefID string) {
+	log.Println(2, "\tThe operation %s it depends on has been skipped.", log.Style.Op(opRefID))
+}
+
+// UsingFallback informs that an operation uses the fallback parameters
+// because some operation it depends on has been skipped.
+func (log *Log) UsingFallback(opRefID string) {
+	log.Println(2, "\tThe operation %s has been skipped, using the fallback parameters.", log.Style.Op(opRefID))
+}
+
+// Polling informs that an operation is going to be repeated because
+// the condition on it's response doesn't hold yet.
+func (log *Log) Polling(condition string, attempt int64, delay time.Duration) {
+	log.Println(3, "\tAttempt #%d: %s doesn't hold yet, retrying in %s.", attempt, log.Style.Value(condition), log.Style.Duration(delay))
+}
+
+// PollingGaveUp informs that the condition on an operation response
+// didn't hold after all the attempts.
+func (log *Log) PollingGaveUp(condition string, attempts int64) {
+	log.Println(2, "\tGave up waiting for %s after %d attempts.", log.Style.ValueExpected(condition), attempts)
+}
+
+// LoadStarting informs about the load test parameters.
+func (log *Log) LoadStarting(rps int64, duration time.Duration, workers int64) {
+	log.Println(1, "Load testing at %s rps for %s with %s workers...",
+		log.Style.Value(rps),
+		log.Style.Duration(duration),
+		log.Style.Value(workers),
+	)
+}
+
+// LoadReport prints a load test summary.
+func (log *Log) LoadReport(report contract.LoadReport) {
+	percent := func(n int) string {
+		if report.Requests == 0 {
+			return "0.00%"
+		}
+
+		return fmt.Sprintf("%.2f%%", float64(n)/float64(report.Requests)*100)
+	}
+
+	statusName := func(status int) string {
+		if status == 0 {
+			return "no response"
+		}
+
+		return fmt.Sprintf("status %d", status)
+	}
+
+	failures := 0
+	for _, n := range report.Failures {
+		failures += n
+	}
+
+	statuses := []int{}
+	for status := range report.Statuses {
+		statuses = append(statuses, status)
+	}
+
+	sort.Ints(statuses)
+
+	log.Println(1, "")
+	log.Println(1, "Requests: %s in %s (%s rps).",
+		log.Style.Value(report.Requests),
+		log.Style.Duration(report.Duration.Round(time.Millisecond)),
+		log.Style.Value(fmt.Sprintf("%.2f", report.Throughput)),
+	)
+
+	for _, status := range statuses {
+		log.Println(1, "	%s: %s, %s failed.",
+			log.Style.ID(statusName(status)),
+			log.Style.Value(report.Statuses[status]),
+			log.Style.Value(report.Failures[status]),
+		)
+	}
+
+	errorStyle := log.Style.Success
+	if failures > 0 {
+		errorStyle = log.Style.Error
+	}
+
+	log.Println(1, "Failures: %s.", errorStyle(fmt.Sprintf("%d (%s)", failures, percent(failures))))
+
+	schemaStyle := log.Style.Success
+	if report.SchemaFailures > 0 {
+		schemaStyle = log.Style.Error
+	}
+
+	log.Println(1, "Schema failures: %s.", schemaStyle(fmt.Sprintf("%d (%s)", report.SchemaFailures, percent(report.SchemaFailures))))
+
+	log.Println(1, "Latency: p50 %s, p90 %s, p99 %s.",
+		log.Style.Duration(report.P50.Round(time.Microsecond)),
+		log.Style.Duration(report.P90.Round(time.Microsecond)),
+		log.Style.Duration(report.P99.Round(time.Microsecond)),
+	)
+}
+
+// DiffingSpecs informs about the specs being compared.
+func (log *Log) DiffingSpecs(oldPath string, newPath string) {
+	log.Println(2, "Comparing the %s spec with %s.", log.Style.URL(oldPath), log.Style.URL(newPath))
+}
+
+// SpecDiffReport prints the changes between two versions of a spec.
+func (log *Log) SpecDiffReport(changes []contract.SpecChange) {
+	breaking := 0
+
+	for _, change := range changes {
+		kind := log.Style.OK("compatible")
+		if change.Breaking {
+			kind = log.Style.Failure("breaking  ")
+			breaking++
+		}
+
+		where := []string{}
+		if change.Operation != "" {
+			where = append(where, log.Style.Op(change.Operation))
+		}
+
+		if change.Location != "" {
+			where = append(where, log.Style.ID(change.Location))
+		}
+
+		log.Println(1, "\t%s %s: %s", kind, strings.Join(where, " "), change.Message)
+	}
+
+	breakingStyle := log.Style.Success
+	if breaking > 0 {
+		breakingStyle = log.Style.Error
+	}
+
+	log.Println(1, "Found %s changes, %s of them breaking.", log.Style.Value(len(changes)), breakingStyle(breaking))
+}
+
+// CoverageReport prints the API coverage of the specs.
+func (log *Log) CoverageReport(reports []contract.CoverageReport) {
+	mark := func(covered bool) string {
+		if covered {
+			return log.Style.OK("+")
+		}
+
+		return log.Style.Failure("-")
+	}
+
+	for _, report := range reports {
+		log.Println(1, "")
+		log.Println(1, "Coverage of the %s spec (%s): %s, %s of %s.",
+			log.Style.Op(report.Title),
+			log.Style.URL(report.Spec),
+			log.Style.Value(fmt.Sprintf("%.1f%%", report.Percent)),
+			log.Style.Value(report.Covered),
+			log.Style.Value(report.Total),
+		)
+
+		for _, opc := range report.Operations {
+			log.Println(1, "\t%s %s [%s]", mark(opc.Covered), log.Style.Op(opc.Operation), log.Style.ID(opc.ID))
+
+			for _, item := range opc.Responses {
+				log.Println(2, "\t\t%s response %s", mark(item.Covered), item.Name)
+			}
+
+			for _, item := range opc.Parameters {
+				log.Println(2, "\t\t%s parameter %s", mark(item.Covered), item.Name)
+			}
+
+			for _, item := range opc.Branches {
+				log.Println(2, "\t\t%s branch %s", mark(item.Covered), item.Name)
+			}
+		}
+	}
+}
+
+// VerifyingTraffic informs about the recorded traffic being checked against a spec.
+func (log *Log) VerifyingTraffic(path string, pi contract.ProjectInfo) {
+	log.Println(1, "Verifying the %s traffic against the %s @ %s.", log.Style.URL(path), log.Style.Op(pi.Title()), log.Style.ID(pi.Version()))
+}
+
+// ConformanceReport prints the outcome of checking the recorded traffic against a spec.
+func (log *Log) ConformanceReport(report contract.ConformanceReport) {
+	for _, entry := range report.Entries {
+		mark := log.Style.OK("+")
+		status := log.Style.Value(entry.Status)
+		if entry.Aborted {
+			mark = log.Style.Skipped("~")
+			status = log.Style.Skipped("aborted")
+		} else if !entry.Conformant {
+			mark = log.Style.Failure("-")
+		}
+
+		op := ""
+		if entry.Operation != "" {
+			op = " [" + log.Style.Op(entry.Operation) + "]"
+		}
+
+		log.Println(1, "\t%s %s %s: %s%s", mark, entry.Method, log.Style.URL(entry.URL), status, op)
+
+		for _, v := range entry.Violations {
+			if v.Location == "" {
+				log.Println(1, "\t\t%s", v.Message)
+			} else {
+				log.Println(1, "\t\t%s: %s", log.Style.ID(v.Location), v.Message)
+			}
+		}
+	}
+
+	log.Println(1, "")
+	log.Println(1, "%s of %s exchanges conform to the spec (%s).",
+		log.Style.Value(report.Conformant),
+		log.Style.Value(report.Total),
+		log.Style.Value(fmt.Sprintf("%.1f%%", report.Percent)),
+	)
+
+	if report.Aborted > 0 {
+		log.Println(1, "%s aborted exchanges are skipped.", log.Style.Value(report.Aborted))
+	}
+}
+
+// MockServing informs that the mock server has started.
+func (log *Log) MockServing(pi contract.ProjectInfo, address string) {
+	log.Println(1, "Serving the %s @ %s mock on %s.", log.Style.Op(pi.Title()), log.Style.ID(pi.Version()), log.Style.URL(address))
+}
+
+// ProxyServing informs that the validating proxy has started.
+func (log *Log) ProxyServing(pi contract.ProjectInfo, address string, target string) {
+	log.Println(1, "Proxying %s to %s, validating against the %s @ %s.", log.Style.URL(address), log.Style.URL(target), log.Style.Op(pi.Title()), log.Style.ID(pi.Version()))
+}
+
+// ServedRequest informs about a request served by the mock server or the proxy.
+func (log *Log) ServedRequest(method string, URL string, status int) {
+	statusStyle := log.Style.Success
+	if status >= 400 {
+		statusStyle = log.Style.Error
+	}
+
+	log.Println(1, "%s %s: %s", method, log.Style.URL(URL), statusStyle(status))
+}
+
+// Violation informs about a request or a response which doesn't conform to the spec.
+func (log *Log) Violation(location string, msg string) {
+	if location == "" {
+		log.Println(1, "\t%s", msg)
+		return
+	}
+
+	log.Println(1, "\t%s: %s", log.Style.ID(location), msg)
+}
+
 // Flush does nothing for the regular logger.
 func (log *Log) Flush() {
 	log.Output.Flush()
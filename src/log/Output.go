@@ -2,6 +2,7 @@ package log
 
 import (
 	"fmt"
+	"io"
 )
 
 // BufferedStdOut is a buffered output for log.
@@ -19,7 +20,7 @@ func NewBufferedStdOut() *BufferedStdOut {
 // Flush flushes the accumulated output to stdout.
 func (buffer *BufferedStdOut) Flush() {
 	// fmt.Print("Flushing\n")
-	fmt.Printf(buffer.data)
+	fmt.Print(buffer.data)
 	buffer.data = ""
 }
 
@@ -29,6 +30,27 @@ func (buffer *BufferedStdOut) Print(msg string, args ...interface{}) {
 	buffer.data += data
 }
 
+// WriterOutput is an output for Log which writes to an io.Writer, like a file.
+type WriterOutput struct {
+	Writer io.Writer
+}
+
+// NewWriterOutput creates a new WriterOutput instance.
+func NewWriterOutput(w io.Writer) WriterOutput {
+	return WriterOutput{
+		Writer: w,
+	}
+}
+
+// Print writes the message to the writer.
+func (out WriterOutput) Print(msg string, args ...interface{}) {
+	fmt.Fprintf(out.Writer, msg, args...)
+}
+
+// Flush does nothing for writer output.
+func (out WriterOutput) Flush() {
+}
+
 // StdOut is a standard terminal output for Log.
 type StdOut struct {
 	///
@@ -37,6 +37,11 @@ func (log Plain) Failure(args ...interface{}) string {
 	return fmt.Sprint(args...)
 }
 
+// Skipped marks up the skipped operation testing result.
+func (log Plain) Skipped(args ...interface{}) string {
+	return fmt.Sprint(args...)
+}
+
 // Success marks up the successful testing of operation properties.
 func (log Plain) Success(args ...interface{}) string {
 	return fmt.Sprint(args...)
@@ -66,3 +71,8 @@ func (log Plain) ValueActual(args ...interface{}) string {
 func (log Plain) Value(args ...interface{}) string {
 	return fmt.Sprint(args...)
 }
+
+// Duration marks up time durations.
+func (log Plain) Duration(args ...interface{}) string {
+	return fmt.Sprint(args...)
+}
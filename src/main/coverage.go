@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+// Coverage collects the API coverage of the executed operations.
+// Specs are identified by their file paths, because scripts
+// with datasets load them anew for every row.
+type Coverage map[string]*openapi3.Coverage
+
+// Spec returns the coverage of the spec, creating it when needed.
+// Only OAS3 specs are supported, for the others it returns nil.
+func (cov Coverage) Spec(spec contract.Spec) *openapi3.Coverage {
+	oasSpec, ok := spec.(*openapi3.Spec)
+	if !ok {
+		return nil
+	}
+
+	if cov[oasSpec.Path] == nil {
+		cov[oasSpec.Path] = openapi3.NewCoverage(oasSpec)
+	}
+
+	return cov[oasSpec.Path]
+}
+
+// Add adds an operation result to the coverage of the spec.
+func (cov Coverage) Add(spec contract.Spec, op contract.Operation, result *contract.OperationResult) {
+	if specCov := cov.Spec(spec); specCov != nil {
+		specCov.Add(op, result)
+	}
+}
+
+// Graph adds the results of all the operations of an executed script graph.
+func (cov Coverage) Graph(s contract.Script, graph gcontract.Graph) {
+	specs := s.GetSpecs()
+
+	// The specs with no executed operations are reported too.
+	for _, spec := range specs {
+		cov.Spec(spec)
+	}
+
+	for n := range graph.Nodes().Range() {
+		node := n.(*script.ExecutionNode)
+		for _, result := range node.Iterations.Results {
+			cov.Add(specs[node.SpecID], node.Operation, result)
+		}
+	}
+}
+
+// Reports creates coverage reports for all the specs, ordered by their paths.
+func (cov Coverage) Reports() []contract.CoverageReport {
+	paths := []string{}
+	for path := range cov {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	reports := []contract.CoverageReport{}
+	for _, path := range paths {
+		reports = append(reports, cov[path].Report())
+	}
+
+	return reports
+}
+
+// Report outputs the coverage reports in the args.Format format,
+// either to the file from the "coverage to" argument or to stdout.
+func (cov Coverage) Report(args *env.Args, logger contract.Logger) {
+	var out io.Writer = os.Stdout
+
+	if args.Coverage.Path != "" {
+		file, err := os.Create(args.Coverage.Path)
+		if err != nil {
+			errors.Report(err, "Coverage", logger)
+		}
+
+		defer file.Close()
+		out = file
+	}
+
+	reports := cov.Reports()
+
+	switch args.Format {
+	case "text":
+		if args.Coverage.Path != "" {
+			logger = &log.Log{
+				Level:  2,
+				Style:  log.Plain{},
+				Output: log.NewWriterOutput(out),
+			}
+		}
+
+		logger.CoverageReport(reports)
+
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		enc.Encode(reports)
+
+	case "html":
+		err := coverageHTML.Execute(out, reports)
+		if err != nil {
+			errors.Report(err, "Coverage", logger)
+		}
+
+	default:
+		errors.Report(errors.NotFound("Output format", args.Format, nil), "Coverage", logger)
+	}
+}
+
+var coverageHTML = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API coverage</title>
+<style>
+	body { font-family: sans-serif; margin: 2em; }
+	table { border-collapse: collapse; margin-bottom: 2em; }
+	th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+	ul { list-style: none; margin: 0; padding: 0; }
+	.covered { color: #080; }
+	.missing { color: #c00; }
+</style>
+</head>
+<body>
+{{range .}}
+<h2>{{.Title}} &mdash; {{printf "%.1f" .Percent}}%</h2>
+<p>{{.Spec}}: {{.Covered}} of {{.Total}} covered.</p>
+<table>
+	<tr><th>Operation</th><th>Responses</th><th>Parameters</th><th>Branches</th></tr>
+	{{range .Operations}}
+	<tr>
+		<td class="{{if .Covered}}covered{{else}}missing{{end}}">{{.Operation}}<br/><small>{{.ID}}</small></td>
+		<td><ul>{{range .Responses}}<li class="{{if .Covered}}covered{{else}}missing{{end}}">{{.Name}}</li>{{end}}</ul></td>
+		<td><ul>{{range .Parameters}}<li class="{{if .Covered}}covered{{else}}missing{{end}}">{{.Name}}</li>{{end}}</ul></td>
+		<td><ul>{{range .Branches}}<li class="{{if .Covered}}covered{{else}}missing{{end}}">{{.Name}}</li>{{end}}</ul></td>
+	</tr>
+	{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/utility"
+)
+
+// DiffOutput is the JSON output of the diff mode.
+type DiffOutput struct {
+	Old      string                `json:"old"`
+	New      string                `json:"new"`
+	Breaking int                   `json:"breaking"`
+	Changes  []contract.SpecChange `json:"changes"`
+}
+
+// Diff is an entry point for the spec comparison mode.
+// It prints the changes between two versions of a spec
+// and exits with an error code when some of them are breaking.
+func Diff(args *env.Args, logger contract.Logger) {
+	// Only errors are logged when the output is JSON.
+	loadLogger := logger
+	if args.Format == "json" {
+		loadLogger = log.New(args.LogStyle, 1)
+	}
+
+	oldSpec := LoadOAS3(args.Diff.Old, loadLogger)
+	newSpec := LoadOAS3(args.Diff.New, loadLogger)
+
+	changes := openapi3.Diff(oldSpec, newSpec)
+
+	switch args.Format {
+	case "json":
+		output := DiffOutput{
+			Old:     args.Diff.Old,
+			New:     args.Diff.New,
+			Changes: changes,
+		}
+
+		for _, change := range changes {
+			if change.Breaking {
+				output.Breaking++
+			}
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(output)
+
+	case "text":
+		logger.DiffingSpecs(args.Diff.Old, args.Diff.New)
+		logger.SpecDiffReport(changes)
+
+	default:
+		errors.Report(errors.NotFound("Output format", args.Format, nil), "Diff", logger)
+	}
+
+	if openapi3.Breaking(changes) {
+		os.Exit(255)
+	}
+}
+
+// LoadOAS3 loads an OAS3 spec file.
+func LoadOAS3(path string, logger contract.Logger) *openapi3.Spec {
+	spec := utility.Load(path, logger)
+
+	oasSpec, ok := spec.(*openapi3.Spec)
+	if !ok {
+		// That's a NullSpec, it reports the loading error.
+		spec.Title()
+	}
+
+	return oasSpec
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"sync/atomic"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+	"github.com/x1n13y84issmd42/oasis/src/test/load"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+	"github.com/x1n13y84issmd42/oasis/src/utility"
+)
+
+// Load is an entry point for the load testing mode.
+// It executes either operations from a spec or an entire script graph
+// repeatedly, without logging individual requests, and prints a summary.
+func Load(args *env.Args, logger contract.Logger) {
+	runner := load.Runner{
+		RPS:      args.Load.RPS,
+		Duration: args.Load.Duration,
+		Workers:  args.Load.Workers,
+	}
+
+	if err := runner.Validate(); err != nil {
+		errors.Report(err, "Load", logger)
+	}
+
+	// Every operation clones this one, so schema failures are tracked per operation.
+	quiet := load.NewLogger(log.New(args.LogStyle, 0))
+
+	var job load.Job
+
+	if args.Script != "" {
+		logger.LoadingScript(args.Script)
+
+		_, rows, err := script.LoadDataset(args.Script)
+		if err != nil {
+			errors.Report(err, "Load", logger)
+		}
+
+		// The script is loaded once for every dataset row, so the disk & parsing
+		// are not a part of the load, and every run uses the next row.
+		varSets := []script.VarMap{script.VarMap(args.Vars)}
+		if len(rows) > 0 {
+			varSets = []script.VarMap{}
+			for _, row := range rows {
+				varSets = append(varSets, script.RowVars(script.VarMap(args.Vars), row))
+			}
+		}
+
+		scripts, err := script.LoadAll(args.Script, varSets, quiet)
+		if err != nil {
+			errors.Report(err, "Load", logger)
+		}
+
+		counter := uint64(0)
+
+		job = func(stats *load.Stats) {
+			i := atomic.AddUint64(&counter, 1) - 1
+
+			// Operations keep their results, so every run needs a fresh script.
+			s := scripts[i%uint64(len(scripts))].Fresh()
+			graph := s.GetExecutionGraph()
+
+			script.NewExecutor(quiet, s).Execute(graph)
+
+			// Skipped operations have no iterations.
+			for n := range graph.Nodes().Range() {
+				node := n.(*script.ExecutionNode)
+				for _, result := range node.Iterations.Results {
+					stats.Add(result, load.SchemaFailed(node.Operation.GetLogger()))
+				}
+			}
+		}
+	} else {
+		spec := utility.Load(args.Spec, logger)
+		logger.TestingProject(spec)
+
+		specOps := utility.NewOperationResolver(spec, logger).Resolve(args.Ops)
+		if len(specOps) == 0 {
+			logger.PrintOperations(spec.Operations())
+			return
+		}
+
+		quietSpec := utility.Load(args.Spec, quiet)
+		counter := uint64(0)
+
+		job = func(stats *load.Stats) {
+			i := atomic.AddUint64(&counter, 1) - 1
+
+			// Operations keep their results, so every request needs a fresh one.
+			op := quietSpec.GetOperation(specOps[i%uint64(len(specOps))].ID())
+			oplog := op.GetLogger()
+
+			enrichment, v := SetupOperation(op, args, oplog)
+			result := test.Operation(op, &enrichment, v, oplog)
+
+			stats.Add(result, load.SchemaFailed(oplog))
+		}
+	}
+
+	logger.LoadStarting(args.Load.RPS, args.Load.Duration, args.Load.Workers)
+
+	logger.LoadReport(runner.Run(job).Report())
+}
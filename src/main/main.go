@@ -1,21 +1,70 @@
 package main
 
 import (
+	"os"
+	"time"
+
 	"github.com/x1n13y84issmd42/oasis/src/env"
 	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
 )
 
 func main() {
 	args := &env.Args{
+		Format: "text",
+		Mock: env.ArgsMock{
+			Address: ":8080",
+		},
+		Proxy: env.ArgsProxy{
+			Address: ":9000",
+		},
 		LogLevel: 2,
 		LogStyle: "festive",
+		Load: env.ArgsLoad{
+			RPS:      10,
+			Duration: 10 * time.Second,
+			Workers:  10,
+		},
 	}
 
 	env.ParseArgs(args)
 
 	logger := log.New(args.LogStyle, args.LogLevel)
 
-	if args.Script != "" {
+	if len(args.Errors) > 0 {
+		for _, err := range args.Errors {
+			logger.Error(err)
+		}
+
+		os.Exit(255)
+	}
+
+	if args.Seed != nil {
+		params.SeedGenerators(*args.Seed)
+	}
+
+	expect.UpdateSnapshots = args.Update
+	test.StrictRequests = args.Strict
+
+	if args.Record != "" {
+		test.Cassettes = test.NewCassetteDeck(args.Record, true)
+	} else if args.Replay != "" {
+		test.Cassettes = test.NewCassetteDeck(args.Replay, false)
+	}
+
+	if args.Diff.Old != "" {
+		Diff(args, logger)
+	} else if args.Mock.Spec != "" {
+		Mock(args, logger)
+	} else if args.Proxy.Spec != "" {
+		Proxy(args, logger)
+	} else if args.Verify != "" {
+		Verify(args, logger)
+	} else if args.Load.Enabled {
+		Load(args, logger)
+	} else if args.Script != "" {
 		Script(args, logger)
 	} else if args.Spec != "" {
 		Manual(args, logger)
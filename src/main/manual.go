@@ -2,10 +2,15 @@ package main
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/test"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
 	"github.com/x1n13y84issmd42/oasis/src/utility"
 )
 
@@ -18,30 +23,23 @@ func Manual(args *env.Args, logger contract.Logger) {
 	// Resolving the operations.
 	specOps := utility.NewOperationResolver(spec, logger).Resolve(args.Ops)
 	result := test.Success()
+	coverage := Coverage{}
 
 	if len(specOps) > 0 {
 		for _, op := range specOps {
 			logger.TestingOperation(op)
 
-			// Stuffing it with data.
-			op.Data().URL.Load(args.Use.PathParameters)
-			op.Data().URL.Load(op.Resolve().Host(args.Host))
-			op.Data().Query.Load(args.Use.Query)
-			op.Data().Headers.Load(args.Use.Headers)
-			op.Data().Body.Load(args.Use.Body)
+			enrichment, v := SetupOperation(op, args, logger)
 
-			enrichment := []contract.RequestEnrichment{
-				op.Data().Query,
-				op.Data().Headers,
-				op.Data().Body,
-
-				op.Resolve().Security(args.Use.Security),
-			}
+			// Testing.
+			opResult := test.Operation(op, &enrichment, v, logger)
+			coverage.Add(spec, op, opResult)
 
-			v := op.Resolve().Response(args.Expect.Status, args.Expect.CT)
+			result = result.And(opResult)
+		}
 
-			// Testing.
-			result = result.And(test.Operation(op, &enrichment, v, logger))
+		if args.Coverage.Enabled {
+			coverage.Report(args, logger)
 		}
 
 	} else {
@@ -52,3 +50,55 @@ func Manual(args *env.Args, logger contract.Logger) {
 		os.Exit(255)
 	}
 }
+
+// SetupOperation stuffs the operation with data from the command line
+// and creates the request enrichment list & response validator for it.
+func SetupOperation(op contract.Operation, args *env.Args, logger contract.Logger) ([]contract.RequestEnrichment, contract.Validator) {
+	op.Data().URL.Load(params.NewExpressionSource(args.Use.PathParameters, logger))
+	op.Data().URL.Load(op.Resolve().Host(args.Host))
+	op.Data().Query.Load(params.NewExpressionSource(args.Use.Query, logger))
+	op.Data().Headers.Load(params.NewExpressionSource(args.Use.Headers, logger))
+	op.Data().Body.Load(params.NewExpressionSource(args.Use.Body, logger))
+
+	enrichment := []contract.RequestEnrichment{
+		op.Data().Query,
+		op.Data().Headers,
+		op.Data().Body,
+
+		op.Resolve().Security(args.Use.Security),
+		test.NodeID(op.ID()),
+	}
+
+	v := op.Resolve().Response(args.Expect.Status, args.Expect.CT)
+
+	if args.Expect.MaxTime > 0 {
+		v.Expect(expect.MaxTime(args.Expect.MaxTime, logger))
+	}
+
+	if args.Expect.Snapshot.Enabled {
+		v.Expect(expect.MatchesSnapshot(SnapshotOptions(op, args, logger), logger))
+	}
+
+	return enrichment, v
+}
+
+// SnapshotOptions creates the snapshot options for the operation.
+// The snapshots are kept in the "__snapshots__/SPEC_NAME" directory.
+func SnapshotOptions(op contract.Operation, args *env.Args, logger contract.Logger) expect.SnapshotOptions {
+	specName := strings.TrimSuffix(filepath.Base(args.Spec), filepath.Ext(args.Spec))
+
+	opts := expect.SnapshotOptions{
+		Path: filepath.Join("__snapshots__", specName, op.ID()+".json"),
+	}
+
+	for _, selector := range args.Expect.Snapshot.Ignore {
+		path, err := params.ParsePath(selector)
+		if err != nil {
+			errors.Report(err, "Manual", logger)
+		}
+
+		opts.Ignore = append(opts.Ignore, path)
+	}
+
+	return opts
+}
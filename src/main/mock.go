@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Mock is an entry point for the mock server mode.
+// It serves the spec operations until the process is stopped.
+func Mock(args *env.Args, logger contract.Logger) {
+	spec := LoadOAS3(args.Mock.Spec, logger)
+
+	logger.MockServing(spec, args.Mock.Address)
+
+	err := http.ListenAndServe(args.Mock.Address, openapi3.NewMock(spec, logger))
+	if err != nil {
+		errors.Report(err, "Mock", logger)
+	}
+}
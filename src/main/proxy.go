@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Proxy is an entry point for the validating proxy mode.
+// It forwards the traffic until the process is stopped.
+func Proxy(args *env.Args, logger contract.Logger) {
+	spec := LoadOAS3(args.Proxy.Spec, logger)
+
+	target, err := url.Parse(args.Proxy.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		errors.Report(errors.Oops("The proxy target '"+args.Proxy.Target+"' is not a valid URL.", err), "Proxy", logger)
+	}
+
+	logger.ProxyServing(spec, args.Proxy.Address, target.String())
+
+	err = http.ListenAndServe(args.Proxy.Address, openapi3.NewProxy(spec, target, logger))
+	if err != nil {
+		errors.Report(err, "Proxy", logger)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/test/script"
 )
 
@@ -10,8 +11,26 @@ import (
 func Script(args *env.Args, log contract.Logger) {
 	log.LoadingScript(args.Script)
 
-	s := script.Load(args.Script, log)
-	graph := s.GetExecutionGraph()
+	dataset, rows, err := script.LoadDataset(args.Script)
+	if err != nil {
+		errors.Report(err, "Script", log)
+	}
 
-	script.NewExecutor(log, s).Execute(graph)
+	coverage := Coverage{}
+
+	if dataset != nil {
+		ex := script.NewDatasetExecutor(log, dataset, rows)
+		ex.OnExecuted = coverage.Graph
+		ex.Execute(args.Script, args.Vars)
+	} else {
+		s := script.Load(args.Script, args.Vars, log)
+		graph := s.GetExecutionGraph()
+
+		script.NewExecutor(log, s).Execute(graph)
+		coverage.Graph(s, graph)
+	}
+
+	if args.Coverage.Enabled {
+		coverage.Report(args, log)
+	}
 }
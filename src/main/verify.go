@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/har"
+)
+
+// Verify is an entry point for the traffic verification mode.
+// It checks the recorded HTTP exchanges against the spec, prints
+// a conformance report and exits with an error code when some
+// of the exchanges don't conform to the spec.
+func Verify(args *env.Args, logger contract.Logger) {
+	// Only errors are logged when the output is JSON.
+	loadLogger := logger
+	if args.Format == "json" {
+		loadLogger = log.New(args.LogStyle, 1)
+	}
+
+	spec := LoadOAS3(args.Spec, loadLogger)
+
+	traffic, err := har.Load(args.Verify)
+	if err != nil {
+		errors.Report(err, "Verify", logger)
+	}
+
+	if args.Format == "text" {
+		logger.VerifyingTraffic(args.Verify, spec)
+	}
+
+	report := openapi3.Conformance(spec, traffic.Log.Entries, loadLogger)
+
+	switch args.Format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+
+	case "text":
+		logger.ConformanceReport(report)
+
+	default:
+		errors.Report(errors.NotFound("Output format", args.Format, nil), "Verify", logger)
+	}
+
+	if report.Conformant < report.Total {
+		os.Exit(255)
+	}
+}
@@ -0,0 +1,55 @@
+package params
+
+import (
+	"sort"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// AccessSource is a parameter source backed by parameter access functions,
+// such as the ones compiled from templates.
+type AccessSource struct {
+	Data map[string][]contract.Parameter
+}
+
+// NewAccessSource creates a new AccessSource instance.
+func NewAccessSource() *AccessSource {
+	return &AccessSource{
+		Data: map[string][]contract.Parameter{},
+	}
+}
+
+// Add adds a parameter access function along with the name of it's source.
+func (src *AccessSource) Add(pn string, v contract.ParameterAccess, source string) {
+	src.Data[pn] = append(src.Data[pn], contract.Parameter{
+		V:      v,
+		Source: source,
+	})
+}
+
+// Iterate creates an iterable channel.
+func (src *AccessSource) Iterate() contract.ParameterIterator {
+	ch := make(contract.ParameterIterator)
+
+	go func() {
+		keys := []string{}
+		for pn := range src.Data {
+			keys = append(keys, pn)
+		}
+
+		sort.Strings(keys)
+
+		for _, pn := range keys {
+			for _, p := range src.Data[pn] {
+				ch <- contract.ParameterTuple{
+					N:         pn,
+					Parameter: p,
+				}
+			}
+		}
+
+		close(ch)
+	}()
+
+	return ch
+}
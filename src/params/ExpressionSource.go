@@ -0,0 +1,37 @@
+package params
+
+import (
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// ExpressionSource is a parameter source which treats the values
+// of another source as expressions, so they may contain function calls
+// like "$uuid()". It's used for the command line arguments.
+type ExpressionSource struct {
+	contract.EntityTrait
+	Src contract.ParameterSource
+}
+
+// NewExpressionSource creates a new ExpressionSource instance.
+func NewExpressionSource(src contract.ParameterSource, log contract.Logger) *ExpressionSource {
+	return &ExpressionSource{
+		EntityTrait: contract.Entity(log),
+		Src:         src,
+	}
+}
+
+// Iterate creates an iterable channel.
+func (src *ExpressionSource) Iterate() contract.ParameterIterator {
+	ch := make(contract.ParameterIterator)
+
+	go func() {
+		for p := range src.Src.Iterate() {
+			p.V = Expression(p.V(), src.Log)
+			ch <- p
+		}
+
+		close(ch)
+	}()
+
+	return ch
+}
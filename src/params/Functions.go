@@ -1,8 +1,13 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
 )
 
 // Value is the default pass-through function to provide parameters.
@@ -12,6 +17,38 @@ func Value(v string) func() string {
 	}
 }
 
+// Concat composes parameter access functions into one
+// which joins their values together.
+func Concat(vs ...contract.ParameterAccess) contract.ParameterAccess {
+	return func() string {
+		res := strings.Builder{}
+		for _, v := range vs {
+			res.WriteString(v())
+		}
+
+		return res.String()
+	}
+}
+
+// Call composes parameter access functions into one
+// which passes their values to a template function f.
+// Errors returned by f are reported.
+func Call(name string, f TemplateFunc, args []contract.ParameterAccess, log contract.Logger) contract.ParameterAccess {
+	return func() string {
+		vs := []string{}
+		for _, arg := range args {
+			vs = append(vs, arg())
+		}
+
+		res, err := f(vs)
+		if err != nil {
+			errors.Report(errors.Oops("The function '"+name+"' has failed.", err), "Call", log)
+		}
+
+		return res
+	}
+}
+
 // Cast casts the given value to string.
 func Cast(v interface{}) string {
 	if cv, ok := v.(string); ok {
@@ -40,6 +77,17 @@ func Cast(v interface{}) string {
 		return "false"
 	}
 
+	// Collections coming from JSON documents are encoded back to JSON.
+	if _, ok := v.([]interface{}); ok {
+		res, _ := json.Marshal(v)
+		return string(res)
+	}
+
+	if _, ok := v.(map[string]interface{}); ok {
+		res, _ := json.Marshal(v)
+		return string(res)
+	}
+
 	//TODO: this is very questionable :/
 	return fmt.Sprintf("%#v", v)
 }
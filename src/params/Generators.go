@@ -0,0 +1,182 @@
+package params
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Generators keeps the state of the value generator functions, like "$uuid()" or "$sequence(orders)".
+// Random values come from a single source which may be seeded with SeedGenerators
+// to make runs reproducible. Operations executed concurrently draw from the source
+// in whatever order they run, so only their values as a whole are reproducible.
+var Generators = struct {
+	sync.Mutex
+	Rand      *rand.Rand
+	Sequences map[string]int64
+}{
+	Rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	Sequences: map[string]int64{},
+}
+
+// SeedGenerators seeds the random value generators and resets the sequences.
+func SeedGenerators(seed int64) {
+	Generators.Lock()
+	defer Generators.Unlock()
+
+	Generators.Rand = rand.New(rand.NewSource(seed))
+	Generators.Sequences = map[string]int64{}
+}
+
+// RandomBytes returns n random bytes.
+func RandomBytes(n int) []byte {
+	Generators.Lock()
+	defer Generators.Unlock()
+
+	res := make([]byte, n)
+	Generators.Rand.Read(res)
+
+	return res
+}
+
+// RandomInt returns a random integer in the [min, max] range.
+func RandomInt(min int64, max int64) int64 {
+	Generators.Lock()
+	defer Generators.Unlock()
+
+	return min + Generators.Rand.Int63n(max-min+1)
+}
+
+// RandomString returns a random string of n characters from the charset.
+func RandomString(n int, charset string) string {
+	chars := []rune(charset)
+	res := make([]rune, n)
+
+	for i := range res {
+		res[i] = chars[RandomInt(0, int64(len(chars)-1))]
+	}
+
+	return string(res)
+}
+
+// Alphanumeric is the default charset for random strings.
+const Alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// UUID generates a random (version 4) UUID.
+func UUID() string {
+	b := RandomBytes(16)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Sequence returns the next value of the named sequence.
+// Sequences start with start, which is 1 by default.
+func Sequence(name string, start int64) int64 {
+	Generators.Lock()
+	defer Generators.Unlock()
+
+	v, ok := Generators.Sequences[name]
+	if !ok {
+		v = start
+	}
+
+	Generators.Sequences[name] = v + 1
+
+	return v
+}
+
+// Now returns the current time formatted with layout,
+// which is RFC3339 by default, and shifted by the optional duration.
+func Now(args []string) (string, error) {
+	t := time.Now()
+	layout := "RFC3339"
+
+	if len(args) > 0 && args[0] != "" {
+		layout = args[0]
+	}
+
+	if len(args) > 1 {
+		d, err := ParseDuration(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		t = t.Add(d)
+	}
+
+	return FormatDate(t, layout), nil
+}
+
+// GenerateInt is the "$randomInt(min, max)" function.
+func GenerateInt(args []string) (string, error) {
+	bounds := []int64{}
+
+	for _, arg := range args {
+		v, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return "", errors.Oops("The bound '"+arg+"' is not an integer.", err)
+		}
+
+		bounds = append(bounds, v)
+	}
+
+	if bounds[0] > bounds[1] {
+		return "", errors.Oops(fmt.Sprintf("The minimum %d is greater than the maximum %d.", bounds[0], bounds[1]), nil)
+	}
+
+	// The range size must fit into int64, the subtraction overflows otherwise.
+	if size := bounds[1] - bounds[0]; size < 0 || size == math.MaxInt64 {
+		return "", errors.Oops(fmt.Sprintf("The range from %d to %d is too wide.", bounds[0], bounds[1]), nil)
+	}
+
+	return strconv.FormatInt(RandomInt(bounds[0], bounds[1]), 10), nil
+}
+
+// GenerateString is the "$randomString(length, [charset])" function.
+func GenerateString(args []string) (string, error) {
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return "", errors.Oops("The length '"+args[0]+"' is not a positive integer.", err)
+	}
+
+	charset := Alphanumeric
+	if len(args) > 1 && args[1] != "" {
+		charset = args[1]
+	}
+
+	return RandomString(n, charset), nil
+}
+
+// GenerateEmail is the "$email([domain])" function.
+func GenerateEmail(args []string) (string, error) {
+	domain := "example.com"
+	if len(args) > 0 && args[0] != "" {
+		domain = args[0]
+	}
+
+	return "user." + strings.ToLower(RandomString(10, Alphanumeric)) + "@" + domain, nil
+}
+
+// GenerateSequence is the "$sequence(name, [start])" function.
+func GenerateSequence(args []string) (string, error) {
+	start := int64(1)
+
+	if len(args) > 1 {
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return "", errors.Oops("The start '"+args[1]+"' is not an integer.", err)
+		}
+
+		start = v
+	}
+
+	return strconv.FormatInt(Sequence(args[0], start), 10), nil
+}
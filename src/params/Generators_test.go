@@ -0,0 +1,80 @@
+package params_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+func Test_Generators(T *testing.T) {
+	gen := func(src string) string {
+		return params.Expression(src, log.NewPlain(0))()
+	}
+
+	T.Run("Seed", func(T *testing.T) {
+		params.SeedGenerators(42)
+		v1 := gen("$uuid() $randomInt(1, 1000) $randomString(12) $email()")
+
+		params.SeedGenerators(42)
+		v2 := gen("$uuid() $randomInt(1, 1000) $randomString(12) $email()")
+
+		assert.Equal(T, v1, v2)
+	})
+
+	T.Run("uuid", func(T *testing.T) {
+		rx := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+		assert.Regexp(T, rx, gen("$uuid()"))
+		assert.NotEqual(T, gen("$uuid()"), gen("$uuid()"))
+	})
+
+	T.Run("randomInt", func(T *testing.T) {
+		for i := 0; i < 100; i++ {
+			assert.Contains(T, []string{"1", "2", "3"}, gen("$randomInt(1, 3)"))
+		}
+
+		assert.Equal(T, "-9223372036854775808", gen("$randomInt(-9223372036854775808, -9223372036854775808)"))
+		assert.Regexp(T, "^-?\\d+$", gen("$randomInt(-9223372036854775806, 0)"))
+
+		for _, bounds := range [][]string{
+			{"-9223372036854775808", "9223372036854775807"},
+			{"-1", "9223372036854775807"},
+			{"0", "9223372036854775807"},
+		} {
+			_, err := params.GenerateInt(bounds)
+			assert.NotNil(T, err, bounds)
+		}
+
+		v, err := params.GenerateInt([]string{"1", "9223372036854775807"})
+		assert.Nil(T, err)
+		assert.NotEqual(T, "0", v)
+	})
+
+	T.Run("randomString", func(T *testing.T) {
+		assert.Regexp(T, "^[a-zA-Z0-9]{12}$", gen("$randomString(12)"))
+		assert.Regexp(T, "^[ab]{5}$", gen("$randomString(5, ab)"))
+	})
+
+	T.Run("email", func(T *testing.T) {
+		assert.Regexp(T, "^user\\.[a-z0-9]{10}@example\\.com$", gen("$email()"))
+		assert.Regexp(T, "@test\\.org$", gen("$email(test.org)"))
+	})
+
+	T.Run("sequence", func(T *testing.T) {
+		params.SeedGenerators(0)
+		assert.Equal(T, "1-2-10-3", gen("$sequence(a)-$sequence(a)-$sequence(b, 10)-$sequence(a)"))
+	})
+
+	T.Run("now", func(T *testing.T) {
+		assert.Regexp(T, "^\\d{4}-\\d{2}-\\d{2}$", gen("$now(Date, -7d)"))
+		assert.Regexp(T, "^\\d+$", gen("$now(Unix)"))
+	})
+
+	T.Run("Literal", func(T *testing.T) {
+		assert.Equal(T, "#op.response.id $x", gen("#op.response.id $x"))
+		assert.Equal(T, "pa$$word", gen("pa$$word"))
+		assert.Equal(T, "pa$word-1", gen("pa$$word-$randomInt(1, 1)"))
+	})
+}
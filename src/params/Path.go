@@ -0,0 +1,494 @@
+package params
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Path is a parsed reference selector. The selector language is compatible
+// with JSONPath (RFC 9535), except that the root identifier "$" is optional
+// because selectors are always applied to some data, like a response body.
+// See ParsePath for the syntax.
+type Path struct {
+	Selector string
+	Segments []PathSegment
+}
+
+// PathSegment is a single step of a path, like ".name", "[0, 1]" or "..*".
+// Descendant segments apply their selections to the node itself
+// and all of it's descendants.
+type PathSegment struct {
+	Descendant bool
+	Selections []PathSelection
+}
+
+// PathSelection selects nodes from a value. root is the data
+// the whole path is applied to, it's used in filters.
+// The nodes are selected by their keys, which are either
+// object member names (strings) or array indexes (ints).
+type PathSelection interface {
+	Keys(v interface{}, root interface{}) []interface{}
+}
+
+// PathNode is a selected value along with it's location in the data,
+// i.e. the keys leading to it from the root.
+type PathNode struct {
+	Location []interface{}
+	Value    interface{}
+}
+
+// NameSelection selects an object member, like ".name" or "['x-id']".
+type NameSelection struct {
+	Name string
+}
+
+// IndexSelection selects an array element, like "[0]" or "[-1]".
+type IndexSelection struct {
+	Index int
+}
+
+// WildcardSelection selects all the array elements or object member values, "[*]" or ".*".
+type WildcardSelection struct{}
+
+// SliceSelection selects a range of array elements, like "[1:5:2]".
+type SliceSelection struct {
+	Start *int
+	End   *int
+	Step  int
+}
+
+// FilterSelection selects the array elements or object member values
+// for which the filter expression is true, like "[?(@.status=='available')]".
+type FilterSelection struct {
+	Expr FilterExpr
+}
+
+// Singular tells whether the path may select one node at most,
+// i.e. it consists of names & indexes only.
+func (path *Path) Singular() bool {
+	for _, seg := range path.Segments {
+		if seg.Descendant || len(seg.Selections) != 1 {
+			return false
+		}
+
+		switch seg.Selections[0].(type) {
+		case NameSelection, IndexSelection:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Select applies the path to data and returns the selected nodes.
+func (path *Path) Select(data interface{}) []interface{} {
+	return path.SelectFrom(data, data)
+}
+
+// SelectFrom applies the path to v and returns the selected nodes.
+// root is the data which absolute paths in filters are applied to.
+func (path *Path) SelectFrom(v interface{}, root interface{}) []interface{} {
+	res := []interface{}{}
+	for _, node := range path.LocateFrom(v, root) {
+		res = append(res, node.Value)
+	}
+
+	return res
+}
+
+// Locate applies the path to data and returns the selected nodes with their locations.
+func (path *Path) Locate(data interface{}) []PathNode {
+	return path.LocateFrom(data, data)
+}
+
+// LocateFrom applies the path to v and returns the selected nodes with their locations.
+func (path *Path) LocateFrom(v interface{}, root interface{}) []PathNode {
+	nodes := []PathNode{{Location: []interface{}{}, Value: v}}
+
+	for _, seg := range path.Segments {
+		next := []PathNode{}
+
+		for _, node := range nodes {
+			targets := []PathNode{node}
+			if seg.Descendant {
+				targets = Descendants(node)
+			}
+
+			for _, target := range targets {
+				for _, sel := range seg.Selections {
+					for _, key := range sel.Keys(target.Value, root) {
+						next = append(next, target.Child(key))
+					}
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	return nodes
+}
+
+// Access creates a reference access function from the path.
+// Singular paths produce the selected value and report an error when there is none,
+// other paths produce arrays of selected values.
+func (path *Path) Access() ReferenceAccess {
+	return func(v interface{}, log contract.Logger) interface{} {
+		nodes := path.Select(v)
+
+		if !path.Singular() {
+			return nodes
+		}
+
+		if len(nodes) == 0 {
+			errors.Report(errors.Oops("Nothing matches the selector '"+path.Selector+"'.", nil), "Path", log)
+			return nil
+		}
+
+		return nodes[0]
+	}
+}
+
+// Child returns the child node of node at key.
+func (node PathNode) Child(key interface{}) PathNode {
+	location := append(append([]interface{}{}, node.Location...), key)
+
+	switch tv := node.Value.(type) {
+	case []interface{}:
+		return PathNode{Location: location, Value: tv[key.(int)]}
+
+	case map[string]interface{}:
+		return PathNode{Location: location, Value: tv[key.(string)]}
+	}
+
+	return PathNode{Location: location}
+}
+
+// ChildKeys returns the array indexes or the object member names, sorted.
+func ChildKeys(v interface{}) []interface{} {
+	res := []interface{}{}
+
+	switch tv := v.(type) {
+	case []interface{}:
+		for i := range tv {
+			res = append(res, i)
+		}
+
+	case map[string]interface{}:
+		keys := []string{}
+		for k := range tv {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			res = append(res, k)
+		}
+	}
+
+	return res
+}
+
+// Descendants returns node and all of it's descendants in the document order.
+func Descendants(node PathNode) []PathNode {
+	res := []PathNode{node}
+
+	for _, key := range ChildKeys(node.Value) {
+		res = append(res, Descendants(node.Child(key))...)
+	}
+
+	return res
+}
+
+// Replace sets the value at location in data and returns the data.
+// Replacing at the empty location replaces the data itself.
+func Replace(data interface{}, location []interface{}, v interface{}) interface{} {
+	if len(location) == 0 {
+		return v
+	}
+
+	switch tv := data.(type) {
+	case []interface{}:
+		i := location[0].(int)
+		tv[i] = Replace(tv[i], location[1:], v)
+
+	case map[string]interface{}:
+		k := location[0].(string)
+		tv[k] = Replace(tv[k], location[1:], v)
+	}
+
+	return data
+}
+
+// Keys selects an object member.
+func (sel NameSelection) Keys(v interface{}, root interface{}) []interface{} {
+	if obj, ok := v.(map[string]interface{}); ok {
+		if _, ok := obj[sel.Name]; ok {
+			return []interface{}{sel.Name}
+		}
+	}
+
+	return []interface{}{}
+}
+
+// Keys selects an array element. Negative indexes count from the end.
+func (sel IndexSelection) Keys(v interface{}, root interface{}) []interface{} {
+	if arr, ok := v.([]interface{}); ok {
+		i := sel.Index
+		if i < 0 {
+			i += len(arr)
+		}
+
+		if i >= 0 && i < len(arr) {
+			return []interface{}{i}
+		}
+	}
+
+	return []interface{}{}
+}
+
+// Keys selects all the children.
+func (sel WildcardSelection) Keys(v interface{}, root interface{}) []interface{} {
+	return ChildKeys(v)
+}
+
+// Keys selects a range of array elements.
+func (sel SliceSelection) Keys(v interface{}, root interface{}) []interface{} {
+	res := []interface{}{}
+
+	arr, ok := v.([]interface{})
+	if !ok || sel.Step == 0 {
+		return res
+	}
+
+	n := len(arr)
+
+	normalize := func(i int) int {
+		if i < 0 {
+			return n + i
+		}
+
+		return i
+	}
+
+	bound := func(i int, lower int, upper int) int {
+		if i < lower {
+			return lower
+		}
+
+		if i > upper {
+			return upper
+		}
+
+		return i
+	}
+
+	if sel.Step > 0 {
+		start, end := 0, n
+		if sel.Start != nil {
+			start = bound(normalize(*sel.Start), 0, n)
+		}
+
+		if sel.End != nil {
+			end = bound(normalize(*sel.End), 0, n)
+		}
+
+		for i := start; i < end; i += sel.Step {
+			res = append(res, i)
+		}
+	} else {
+		start, end := n-1, -1
+		if sel.Start != nil {
+			start = bound(normalize(*sel.Start), -1, n-1)
+		}
+
+		if sel.End != nil {
+			end = bound(normalize(*sel.End), -1, n-1)
+		}
+
+		for i := start; i > end; i += sel.Step {
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
+
+// Keys selects the children which satisfy the filter.
+func (sel FilterSelection) Keys(v interface{}, root interface{}) []interface{} {
+	res := []interface{}{}
+
+	node := PathNode{Value: v}
+	for _, key := range ChildKeys(v) {
+		if sel.Expr.Eval(node.Child(key).Value, root) {
+			res = append(res, key)
+		}
+	}
+
+	return res
+}
+
+// FilterExpr is a logical expression used in filter selections.
+type FilterExpr interface {
+	Eval(current interface{}, root interface{}) bool
+}
+
+// OrExpr is a logical disjunction, "a || b".
+type OrExpr struct {
+	Left  FilterExpr
+	Right FilterExpr
+}
+
+// AndExpr is a logical conjunction, "a && b".
+type AndExpr struct {
+	Left  FilterExpr
+	Right FilterExpr
+}
+
+// NotExpr is a logical negation, "!a".
+type NotExpr struct {
+	Expr FilterExpr
+}
+
+// ExistsExpr is true when a query selects at least one node, like "@.tags".
+type ExistsExpr struct {
+	Query QueryValue
+}
+
+// CompareExpr compares two values, like "@.price < 10".
+type CompareExpr struct {
+	Left     Comparable
+	Operator string
+	Right    Comparable
+}
+
+// Comparable is an operand of a comparison. Value returns false
+// when there is no value, i.e. a query has selected nothing.
+type Comparable interface {
+	Value(current interface{}, root interface{}) (interface{}, bool)
+}
+
+// LiteralValue is a literal string, number, boolean or null.
+type LiteralValue struct {
+	V interface{}
+}
+
+// QueryValue is a query relative to the current node "@" or to the root "$".
+type QueryValue struct {
+	Path     *Path
+	Absolute bool
+}
+
+// Eval evaluates the expression.
+func (expr OrExpr) Eval(current interface{}, root interface{}) bool {
+	return expr.Left.Eval(current, root) || expr.Right.Eval(current, root)
+}
+
+// Eval evaluates the expression.
+func (expr AndExpr) Eval(current interface{}, root interface{}) bool {
+	return expr.Left.Eval(current, root) && expr.Right.Eval(current, root)
+}
+
+// Eval evaluates the expression.
+func (expr NotExpr) Eval(current interface{}, root interface{}) bool {
+	return !expr.Expr.Eval(current, root)
+}
+
+// Eval evaluates the expression.
+func (expr ExistsExpr) Eval(current interface{}, root interface{}) bool {
+	return len(expr.Query.Nodes(current, root)) > 0
+}
+
+// Eval evaluates the expression.
+func (expr CompareExpr) Eval(current interface{}, root interface{}) bool {
+	l, lok := expr.Left.Value(current, root)
+	r, rok := expr.Right.Value(current, root)
+
+	return Compare(l, lok, expr.Operator, r, rok)
+}
+
+// Value returns the literal value.
+func (lit LiteralValue) Value(current interface{}, root interface{}) (interface{}, bool) {
+	return lit.V, true
+}
+
+// Nodes returns the nodes selected by the query.
+func (q QueryValue) Nodes(current interface{}, root interface{}) []interface{} {
+	if q.Absolute {
+		return q.Path.SelectFrom(root, root)
+	}
+
+	return q.Path.SelectFrom(current, root)
+}
+
+// Value returns the node selected by the query. Queries which select
+// nothing or multiple nodes have no value.
+func (q QueryValue) Value(current interface{}, root interface{}) (interface{}, bool) {
+	nodes := q.Nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+// Compare compares two values as RFC 9535 describes.
+// Absent values are equal to each other only.
+func Compare(l interface{}, lok bool, op string, r interface{}, rok bool) bool {
+	switch op {
+	case "==":
+		return Equal(l, lok, r, rok)
+
+	case "!=":
+		return !Equal(l, lok, r, rok)
+
+	case "<":
+		return Less(l, lok, r, rok)
+
+	case "<=":
+		return Less(l, lok, r, rok) || Equal(l, lok, r, rok)
+
+	case ">":
+		return Less(r, rok, l, lok)
+
+	case ">=":
+		return Less(r, rok, l, lok) || Equal(l, lok, r, rok)
+	}
+
+	return false
+}
+
+// Equal tells whether two values are equal.
+func Equal(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return !lok && !rok
+	}
+
+	return reflect.DeepEqual(l, r)
+}
+
+// Less tells whether l is less than r. Only numbers & strings are comparable.
+func Less(l interface{}, lok bool, r interface{}, rok bool) bool {
+	if !lok || !rok {
+		return false
+	}
+
+	if lf, ok := l.(float64); ok {
+		if rf, ok := r.(float64); ok {
+			return lf < rf
+		}
+	}
+
+	if ls, ok := l.(string); ok {
+		if rs, ok := r.(string); ok {
+			return ls < rs
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,439 @@
+package params
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// ParsePath parses a JSONPath (RFC 9535) selector. The root identifier "$"
+// is optional. Supported are:
+//
+//	.name, ['name'], ["name"]	object members; shorthand names may contain letters, digits, "_" and unicode
+//	[0], [-1]			array elements; negative indexes count from the end
+//	.*, [*]				all the children
+//	[1:5:2]				array slices
+//	..name, ..[0], ..*		recursive descent
+//	['a', 'b', 0]			multiple selections
+//	[?(@.status == 'available')]	filters with ==, !=, <, <=, >, >=, &&, ||, ! and existence tests
+func ParsePath(selector string) (*Path, error) {
+	p := &pathParser{scanner{Src: selector}}
+	p.Skip("$")
+
+	segments, err := p.Segments(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Path{
+		Selector: selector,
+		Segments: segments,
+	}, nil
+}
+
+// pathParser is a recursive descent parser for paths.
+type pathParser struct {
+	scanner
+}
+
+// Error creates a syntax error at the current position.
+func (p *pathParser) Error(details string) error {
+	return errors.SelectorSyntax(p.Src, p.Pos, details, nil)
+}
+
+// Segments parses segments until the end of the source.
+// Inside filters it stops at the first character which can't start a segment.
+func (p *pathParser) Segments(inFilter bool) ([]PathSegment, error) {
+	segments := []PathSegment{}
+
+	for !p.EOF() {
+		pos := p.Pos
+		p.SkipSpace()
+
+		if !p.Next(".") && !p.Next("[") {
+			p.Pos = pos
+			if inFilter {
+				return segments, nil
+			}
+
+			return nil, p.Error("unexpected character")
+		}
+
+		seg, err := p.Segment()
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// Segment parses a single child or descendant segment.
+func (p *pathParser) Segment() (PathSegment, error) {
+	seg := PathSegment{}
+
+	if p.Skip("..") {
+		seg.Descendant = true
+		if p.Next("[") {
+			sels, err := p.Bracket()
+			seg.Selections = sels
+			return seg, err
+		}
+	} else if !p.Skip(".") {
+		sels, err := p.Bracket()
+		seg.Selections = sels
+		return seg, err
+	}
+
+	if p.Skip("*") {
+		seg.Selections = []PathSelection{WildcardSelection{}}
+		return seg, nil
+	}
+
+	name := p.Name()
+	if name == "" {
+		return seg, p.Error("a member name is expected")
+	}
+
+	seg.Selections = []PathSelection{NameSelection{Name: name}}
+	return seg, nil
+}
+
+// Name parses a shorthand member name.
+func (p *pathParser) Name() string {
+	start := p.Pos
+
+	for !p.EOF() {
+		r, n := utf8.DecodeRuneInString(p.Src[p.Pos:])
+		if !(r == '_' || r >= 0x80 || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			break
+		}
+
+		p.Pos += n
+	}
+
+	return p.Src[start:p.Pos]
+}
+
+// Bracket parses a bracketed list of selections.
+func (p *pathParser) Bracket() ([]PathSelection, error) {
+	p.Skip("[")
+	sels := []PathSelection{}
+
+	for {
+		p.SkipSpace()
+
+		sel, err := p.Selection()
+		if err != nil {
+			return nil, err
+		}
+
+		sels = append(sels, sel)
+		p.SkipSpace()
+
+		if p.Skip("]") {
+			return sels, nil
+		}
+
+		if !p.Skip(",") {
+			return nil, p.Error("',' or ']' is expected")
+		}
+	}
+}
+
+// Selection parses a single selection inside brackets.
+func (p *pathParser) Selection() (PathSelection, error) {
+	c := p.Peek()
+
+	switch {
+	case c == '\'' || c == '"':
+		name, err := p.String()
+		return NameSelection{Name: name}, err
+
+	case c == '*':
+		p.Pos++
+		return WildcardSelection{}, nil
+
+	case c == '?':
+		p.Pos++
+		expr, err := p.Or()
+		return FilterSelection{Expr: expr}, err
+
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.IndexOrSlice()
+	}
+
+	return nil, p.Error("a selection is expected")
+}
+
+// Int parses an optional integer. It returns nil when there is none.
+func (p *pathParser) Int() (*int, error) {
+	start := p.Pos
+	p.Skip("-")
+
+	for !p.EOF() && p.Peek() >= '0' && p.Peek() <= '9' {
+		p.Pos++
+	}
+
+	if p.Pos == start {
+		return nil, nil
+	}
+
+	i, err := strconv.Atoi(p.Src[start:p.Pos])
+	if err != nil {
+		p.Pos = start
+		return nil, p.Error("an integer is expected")
+	}
+
+	return &i, nil
+}
+
+// IndexOrSlice parses an index like "-1" or a slice like "1:5:2".
+func (p *pathParser) IndexOrSlice() (PathSelection, error) {
+	start, err := p.Int()
+	if err != nil {
+		return nil, err
+	}
+
+	p.SkipSpace()
+	if !p.Skip(":") {
+		if start == nil {
+			return nil, p.Error("an integer is expected")
+		}
+
+		return IndexSelection{Index: *start}, nil
+	}
+
+	sel := SliceSelection{Start: start, Step: 1}
+
+	p.SkipSpace()
+	if sel.End, err = p.Int(); err != nil {
+		return nil, err
+	}
+
+	p.SkipSpace()
+	if p.Skip(":") {
+		p.SkipSpace()
+		step, err := p.Int()
+		if err != nil {
+			return nil, err
+		}
+
+		if step != nil {
+			sel.Step = *step
+		}
+	}
+
+	return sel, nil
+}
+
+// String parses a single or double quoted string literal.
+func (p *pathParser) String() (string, error) {
+	quote := p.Peek()
+	p.Pos++
+
+	res := strings.Builder{}
+
+	for !p.EOF() {
+		c := p.Peek()
+
+		if c == quote {
+			p.Pos++
+			return res.String(), nil
+		}
+
+		if c != '\\' {
+			r, n := utf8.DecodeRuneInString(p.Src[p.Pos:])
+			res.WriteRune(r)
+			p.Pos += n
+			continue
+		}
+
+		p.Pos++
+		esc := p.Peek()
+		p.Pos++
+
+		switch esc {
+		case '\\', '/', '\'', '"':
+			res.WriteByte(esc)
+		case 'b':
+			res.WriteByte('\b')
+		case 'f':
+			res.WriteByte('\f')
+		case 'n':
+			res.WriteByte('\n')
+		case 'r':
+			res.WriteByte('\r')
+		case 't':
+			res.WriteByte('\t')
+		case 'u':
+			if p.Pos+4 > len(p.Src) {
+				return "", p.Error("a unicode escape is incomplete")
+			}
+
+			code, err := strconv.ParseUint(p.Src[p.Pos:p.Pos+4], 16, 32)
+			if err != nil {
+				return "", p.Error("a unicode escape is invalid")
+			}
+
+			res.WriteRune(rune(code))
+			p.Pos += 4
+		default:
+			p.Pos--
+			return "", p.Error("an unknown escape sequence")
+		}
+	}
+
+	return "", p.Error("a string is not terminated")
+}
+
+// Or parses a logical disjunction, the top level filter expression.
+func (p *pathParser) Or() (FilterExpr, error) {
+	left, err := p.And()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.SkipSpace(); p.Skip("||"); p.SkipSpace() {
+		right, err := p.And()
+		if err != nil {
+			return nil, err
+		}
+
+		left = OrExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// And parses a logical conjunction.
+func (p *pathParser) And() (FilterExpr, error) {
+	left, err := p.Not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.SkipSpace(); p.Skip("&&"); p.SkipSpace() {
+		right, err := p.Not()
+		if err != nil {
+			return nil, err
+		}
+
+		left = AndExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// Not parses an optionally negated basic expression.
+func (p *pathParser) Not() (FilterExpr, error) {
+	p.SkipSpace()
+
+	if p.Next("!") && !p.Next("!=") {
+		p.Pos++
+		expr, err := p.Not()
+		return NotExpr{Expr: expr}, err
+	}
+
+	return p.Basic()
+}
+
+// Basic parses a parenthesized expression, a comparison or an existence test.
+func (p *pathParser) Basic() (FilterExpr, error) {
+	if p.Skip("(") {
+		expr, err := p.Or()
+		if err != nil {
+			return nil, err
+		}
+
+		p.SkipSpace()
+		if !p.Skip(")") {
+			return nil, p.Error("')' is expected")
+		}
+
+		return expr, nil
+	}
+
+	start := p.Pos
+	left, err := p.Comparable()
+	if err != nil {
+		return nil, err
+	}
+
+	p.SkipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.Skip(op) {
+			p.SkipSpace()
+			right, err := p.Comparable()
+			if err != nil {
+				return nil, err
+			}
+
+			return CompareExpr{Left: left, Operator: op, Right: right}, nil
+		}
+	}
+
+	if query, ok := left.(QueryValue); ok {
+		return ExistsExpr{Query: query}, nil
+	}
+
+	p.Pos = start
+	return nil, p.Error("a literal is not a filter")
+}
+
+// Comparable parses a query or a literal.
+func (p *pathParser) Comparable() (Comparable, error) {
+	c := p.Peek()
+
+	switch {
+	case c == '@' || c == '$':
+		p.Pos++
+		segments, err := p.Segments(true)
+		if err != nil {
+			return nil, err
+		}
+
+		path := &Path{
+			Selector: p.Src,
+			Segments: segments,
+		}
+
+		return QueryValue{Path: path, Absolute: c == '$'}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.String()
+		return LiteralValue{V: s}, err
+
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.Number()
+	}
+
+	for kw, v := range map[string]interface{}{"true": true, "false": false, "null": nil} {
+		if p.Skip(kw) {
+			return LiteralValue{V: v}, nil
+		}
+	}
+
+	return nil, p.Error("a query or a literal is expected")
+}
+
+// Number parses a JSON number literal.
+func (p *pathParser) Number() (Comparable, error) {
+	start := p.Pos
+
+	for !p.EOF() && strings.IndexByte("-+.eE0123456789", p.Peek()) >= 0 {
+		p.Pos++
+	}
+
+	f, err := strconv.ParseFloat(p.Src[start:p.Pos], 64)
+	if err != nil {
+		p.Pos = start
+		return nil, p.Error("a number is invalid")
+	}
+
+	return LiteralValue{V: f}, nil
+}
@@ -0,0 +1,118 @@
+package params_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+func Test_Path(T *testing.T) {
+	var data interface{}
+	json.Unmarshal([]byte(`{
+		"pets": [
+			{"id": 1, "status": "available", "x-id": "a1", "tags": ["cute"]},
+			{"id": 2, "status": "sold", "x-id": "a2"},
+			{"id": 3, "status": "available", "x-id": "a3", "price": 10}
+		],
+		"owner": {"name": "Ann", "имя": "Анна", "2fa": true, "id": 42}
+	}`), &data)
+
+	selectors := map[string][]interface{}{
+		"":                                      {data},
+		"$.owner.name":                          {"Ann"},
+		".owner.имя":                            {"Анна"},
+		".owner.2fa":                            {true},
+		"['owner'][\"name\"]":                   {"Ann"},
+		".pets[-1].id":                          {float64(3)},
+		".pets[5].id":                           {},
+		".pets[*].id":                           {float64(1), float64(2), float64(3)},
+		".pets[*]['x-id']":                      {"a1", "a2", "a3"},
+		".pets[0:2].id":                         {float64(1), float64(2)},
+		".pets[::-1].id":                        {float64(3), float64(2), float64(1)},
+		".pets[0, -1].id":                       {float64(1), float64(3)},
+		"..id":                                  {float64(42), float64(1), float64(2), float64(3)},
+		".owner.*":                              {true, float64(42), "Ann", "Анна"},
+		".pets[?(@.status=='available')].id":    {float64(1), float64(3)},
+		".pets[?@.status != 'available'].id":    {float64(2)},
+		".pets[?(@.tags)].id":                   {float64(1)},
+		".pets[?!@.tags && @.id > 1].id":        {float64(2), float64(3)},
+		".pets[?@.price <= 10 || @.id == 2].id": {float64(2), float64(3)},
+		".pets[?@.id == $.owner.id].id":         {},
+		".pets[?(@.status=='sold' || (@.id<2))].id": {float64(1), float64(2)},
+	}
+
+	for selector, expected := range selectors {
+		selector, expected := selector, expected
+		T.Run(selector, func(T *testing.T) {
+			path, err := params.ParsePath(selector)
+			assert.Nil(T, err)
+			assert.Equal(T, expected, path.Select(data))
+		})
+	}
+}
+
+func Test_PathLocate(T *testing.T) {
+	var data interface{}
+	json.Unmarshal([]byte(`{"pets": [{"id": 1, "at": "today"}, {"id": 2}], "at": "now"}`), &data)
+
+	path, err := params.ParsePath("..at")
+	assert.Nil(T, err)
+
+	nodes := path.Locate(data)
+	assert.Equal(T, []params.PathNode{
+		{Location: []interface{}{"at"}, Value: "now"},
+		{Location: []interface{}{"pets", 0, "at"}, Value: "today"},
+	}, nodes)
+
+	for _, node := range nodes {
+		data = params.Replace(data, node.Location, nil)
+	}
+
+	assert.Equal(T, map[string]interface{}{
+		"at": nil,
+		"pets": []interface{}{
+			map[string]interface{}{"id": float64(1), "at": nil},
+			map[string]interface{}{"id": float64(2)},
+		},
+	}, data)
+
+	assert.Equal(T, "root", params.Replace(data, []interface{}{}, "root"))
+}
+
+func Test_PathSingular(T *testing.T) {
+	for selector, expected := range map[string]bool{
+		"":              true,
+		".pets[0].id":   true,
+		"['x-id']":      true,
+		".pets[*].id":   false,
+		"..id":          false,
+		".pets[0,1]":    false,
+		".pets[?@.id]":  false,
+		".pets[0:1].id": false,
+	} {
+		path, err := params.ParsePath(selector)
+		assert.Nil(T, err)
+		assert.Equal(T, expected, path.Singular(), selector)
+	}
+}
+
+func Test_PathErrors(T *testing.T) {
+	for selector, pos := range map[string]int{
+		".":               1,
+		".pets[":          6,
+		".pets[0":         7,
+		"['x-id":          6,
+		".pets[?(@.id]":   12,
+		".pets[?'x']":     7,
+		".pets[? @.id ==": 15,
+		"pets":            0,
+	} {
+		_, err := params.ParsePath(selector)
+		serr, ok := err.(errors.ErrSelectorSyntax)
+		assert.True(T, ok, selector)
+		assert.Equal(T, pos, serr.Pos, selector)
+	}
+}
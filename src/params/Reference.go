@@ -1,14 +1,25 @@
 package params
 
 import (
-	goerrors "errors"
+	"encoding/json"
+	"net/url"
 	"regexp"
 	"strconv"
 
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/strings"
-	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// Parts of operation requests & responses which references may point at.
+const (
+	PartResponseBody    = "response.body"
+	PartResponseStatus  = "response.status"
+	PartResponseHeaders = "response.headers"
+	PartResponseCookies = "response.cookies"
+	PartRequestBody     = "request.body"
+	PartRequestQuery    = "request.query"
+	PartRequestPath     = "request.path"
 )
 
 // Reference is a special kind of parameter which comes from
@@ -16,103 +27,212 @@ import (
 // has a value like "operationID.response.[0].user.id" this means that
 // the actual value comes from JSON response of the operation "operationID"
 // and it's exact location is "[0].user.id" field.
+// References may also point at other parts of the request & response,
+// see Part & SplitPart; the response body is the default one.
 type Reference struct {
 	OpID     string
 	Result   *contract.OperationResult
+	Part     string
 	Selector string
 
 	Log contract.Logger
 }
 
+// SplitPart extracts the referenced request or response part from the selector
+// which goes after "response" or "request" in a reference.
+// F.e. ("response", ".headers[Location]") becomes ("response.headers", "[Location]"),
+// and ("response", "[0].id") becomes ("response.body", "[0].id").
+func SplitPart(kind string, selector string) (string, string) {
+	rx := regexp.MustCompile("^\\.(?P<part>[a-z]+)(?P<selector>([\\.\\[].*)?)$")
+	matches := strings.RxMatches(selector, rx)
+
+	parts := map[string][]string{
+		"response": {"body", "status", "headers", "cookies"},
+		"request":  {"body", "query", "path"},
+	}
+
+	for _, part := range parts[kind] {
+		if matches["part"] == part {
+			return kind + "." + part, matches["selector"]
+		}
+	}
+
+	// Response selectors point at the body by default.
+	return kind + ".body", selector
+}
+
 // Value returns a parameter access function which computes and returns a real value.
+// Missing values are reported.
 func (pr Reference) Value() contract.ParameterAccess {
 	return func() string {
-		access, _ := ParseSelector(pr.Selector, pr.Log)
+		v, ok := pr.Lookup()
+		if !ok {
+			errors.Report(errors.Oops("The reference '"+pr.String()+"' has no value.", nil), "Reference", pr.Log)
+		}
+
+		return v
+	}
+}
 
-		var data interface{}
-		var err error
+// String returns the reference the way it's written in scripts, like "#getPet.response.body.id".
+func (pr Reference) String() string {
+	return "#" + pr.OpID + "." + pr.Part + pr.Selector
+}
+
+// Lookup computes the referenced value. It returns false when there is no value,
+// like when a field is missing, a response has not been received yet
+// or it's body is not a JSON.
+func (pr Reference) Lookup() (string, bool) {
+	if pr.Result == nil {
+		return "", false
+	}
 
-		if res, err := test.TryJSONObjectResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			return pr.Cast(access(res, pr.Log))
+	switch pr.Part {
+	case PartResponseStatus:
+		if pr.Result.HTTPResponse == nil {
+			return "", false
 		}
 
-		if res, err := test.TryJSONArrayResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			return pr.Cast(access(res, pr.Log))
+		return strconv.Itoa(pr.Result.HTTPResponse.StatusCode), true
+
+	case PartResponseHeaders:
+		if pr.Result.HTTPResponse == nil || len(pr.Result.HTTPResponse.Header.Values(pr.Key())) == 0 {
+			return "", false
 		}
 
-		if res, err := test.TryJSONStringResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
+		return pr.Result.HTTPResponse.Header.Get(pr.Key()), true
+
+	case PartResponseCookies:
+		if pr.Result.HTTPResponse == nil {
+			return "", false
 		}
 
-		if res, err := test.TryJSONNumberResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
+		for _, cookie := range pr.Result.HTTPResponse.Cookies() {
+			if cookie.Name == pr.Key() {
+				return cookie.Value, true
+			}
 		}
 
-		if res, err := test.TryJSONBooleanResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
+		return "", false
+
+	case PartRequestQuery:
+		if pr.Result.HTTPRequest == nil {
+			return "", false
 		}
 
-		if err != nil {
-			pr.Log.Error(err)
+		vs, ok := pr.Result.HTTPRequest.URL.Query()[pr.Key()]
+		if !ok || len(vs) == 0 {
+			return "", false
 		}
 
-		return pr.Cast(access(data, pr.Log))
+		return vs[0], true
+
+	case PartRequestPath:
+		v, ok := pr.Result.PathParameters[pr.Key()]
+		return v, ok
+
+	case PartRequestBody:
+		if !json.Valid(pr.Result.RequestBytes) {
+			form, err := url.ParseQuery(string(pr.Result.RequestBytes))
+			if err != nil || len(form[pr.Key()]) == 0 {
+				return "", false
+			}
+
+			return form.Get(pr.Key()), true
+		}
+
+		return pr.JSON(pr.Result.RequestBytes)
 	}
+
+	return pr.JSON(pr.Result.ResponseBytes)
 }
 
-// Cast casts the given value to string.
-func (pr Reference) Cast(v interface{}) string {
-	return Cast(v)
+// LookupKey is Key which returns an empty name instead of reporting invalid selectors.
+func (pr Reference) Key() string {
+	matches := strings.RxMatches(pr.Selector, pr.KeyRx())
+
+	if matches["field"] != "" {
+		return matches["field"]
+	}
+
+	return matches["index"]
 }
 
-// ReferenceAccess is a function to compute and return a referenced value.
-type ReferenceAccess func(interface{}, contract.Logger) interface{}
+// LookupJSON selects a value from JSON data. It returns false when the data is not a JSON
+// or when a singular selector selects nothing.
+func (pr Reference) JSON(jsonBytes []byte) (string, bool) {
+	path, err := ParsePath(pr.Selector)
+	if err != nil {
+		return "", false
+	}
 
-// ParseArrayIndexRef parses the JSON array index signature [N]
-// in the beginning of selector. It returns the index and number
-// of parsed characters when successful, and (-1, 0) when it was not able to parse.
-func ParseArrayIndexRef(selector string) (int, int) {
-	rx := regexp.MustCompile("^\\[(?P<index>\\d+)\\]")
-	matches := strings.RxMatches(selector, rx)
-	if matches["index"] != "" {
-		// Ignoring the error because regexp requires an integer.
-		idx, _ := strconv.Atoi(matches["index"])
-		return idx, len(matches["index"]) + 2
+	var data interface{}
+	if json.Unmarshal(jsonBytes, &data) != nil {
+		return "", false
+	}
+
+	nodes := path.Select(data)
+
+	if !path.Singular() {
+		return pr.Cast(nodes), true
+	}
+
+	if len(nodes) == 0 {
+		return "", false
 	}
 
-	return -1, 0
+	return pr.Cast(nodes[0]), true
 }
 
-// ParseObjectFieldRef parses the JSON object field signature .fieldName
-// in the beginning of selector. It returns the field name and number
-// of parsed characters when successful, and ("", 0) when it was not able to parse.
-func ParseObjectFieldRef(selector string) (string, int) {
-	rx := regexp.MustCompile("^\\.(?P<field>[a-zA-Z_]+)")
-	matches := strings.RxMatches(selector, rx)
-	if matches["field"] != "" {
-		return matches["field"], len(matches["field"]) + 1
+// Validate checks the reference selector syntax, so errors in it
+// are reported before any requests are made.
+func (pr Reference) Validate() error {
+	switch pr.Part {
+	case PartResponseStatus:
+		if pr.Selector != "" {
+			return errors.SelectorSyntax(pr.Selector, 0, "the status code has no properties", nil)
+		}
+
+	case PartResponseHeaders, PartResponseCookies, PartRequestQuery, PartRequestPath:
+		if !pr.KeyRx().MatchString(pr.Selector) {
+			return errors.SelectorSyntax(pr.Selector, 0, "a name like '.name' or '[name]' is expected", nil)
+		}
+
+	default:
+		_, err := ParsePath(pr.Selector)
+		return err
 	}
 
-	return "", 0
+	return nil
+}
+
+// KeyRx is a regular expression for selectors of headers, cookies & parameters.
+func (pr Reference) KeyRx() *regexp.Regexp {
+	return regexp.MustCompile("^(\\.(?P<field>.+)|\\[['\"]?(?P<index>[^'\"]+)['\"]?\\])$")
+}
+
+// Cast casts the given value to string.
+func (pr Reference) Cast(v interface{}) string {
+	return Cast(v)
 }
 
+// ReferenceAccess is a function to compute and return a referenced value.
+type ReferenceAccess func(interface{}, contract.Logger) interface{}
+
 // ParseSelector parses selectors and constructs a parameter access function from it.
+// When the selector is invalid, it returns the part of it which couldn't be parsed.
+// See ParsePath for the selector syntax.
 func ParseSelector(selector string, log contract.Logger) (ReferenceAccess, string) {
-	res := AccessContent()
-
-	for len(selector) > 0 {
-		if i, n := ParseArrayIndexRef(selector); n > 0 {
-			res = AccessArray(res, i)
-			selector = selector[n:]
-		} else if f, n := ParseObjectFieldRef(selector); n > 0 {
-			res = AccessObject(res, f)
-			selector = selector[n:]
-		} else {
-			return NoAccess(goerrors.New("impossible to parse selector " + selector)), selector
+	path, err := ParsePath(selector)
+	if err != nil {
+		if serr, ok := err.(errors.ErrSelectorSyntax); ok {
+			return NoAccess(err), selector[serr.Pos:]
 		}
+
+		return NoAccess(err), selector
 	}
 
-	return res, ""
+	return path.Access(), ""
 }
 
 // AccessContent passes through.
@@ -27,18 +27,24 @@ func NewReferenceSource(log contract.Logger) *ReferenceSource {
 
 // AddReference adds a reference to a parameter value, located in response of op.
 func (src *ReferenceSource) AddReference(pn string, opID string, result *contract.OperationResult, selector string) {
-	if src.Refs[pn] == nil {
-		src.Refs[pn] = []Reference{}
-	}
-
-	src.Refs[pn] = append(src.Refs[pn], Reference{
+	src.Add(pn, Reference{
 		OpID:     opID,
 		Result:   result,
+		Part:     PartResponseBody,
 		Selector: selector,
 		Log:      src.Log,
 	})
 }
 
+// Add adds a reference to a parameter value.
+func (src *ReferenceSource) Add(pn string, ref Reference) {
+	if src.Refs[pn] == nil {
+		src.Refs[pn] = []Reference{}
+	}
+
+	src.Refs[pn] = append(src.Refs[pn], ref)
+}
+
 // Iterate creates an iterable channel.
 func (src *ReferenceSource) Iterate() contract.ParameterIterator {
 	ch := make(contract.ParameterIterator)
@@ -3,6 +3,7 @@ package params_test
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,9 +143,19 @@ func Test_ParseSelector(T *testing.T) {
 		assert.Equal(T, "", actual)
 	})
 
-	T.Run("[0][200][-1][42] FAIL", func(T *testing.T) {
+	T.Run("[0][200][-1][42] OK", func(T *testing.T) {
 		_, actual := params.ParseSelector("[0][200][-1][42]", log.NewPlain(0))
-		assert.Equal(T, "[-1][42]", actual)
+		assert.Equal(T, "", actual)
+	})
+
+	T.Run("$..pets[?(@.status=='available')]['x-id'] OK", func(T *testing.T) {
+		_, actual := params.ParseSelector("$..pets[?(@.status=='available')]['x-id']", log.NewPlain(0))
+		assert.Equal(T, "", actual)
+	})
+
+	T.Run("[?(@.status==)] FAIL", func(T *testing.T) {
+		_, actual := params.ParseSelector("[?(@.status==)]", log.NewPlain(0))
+		assert.Equal(T, ")]", actual)
 	})
 
 	T.Run(".user name FAIL", func(T *testing.T) {
@@ -187,6 +198,12 @@ func Test_Reference(T *testing.T) {
 		assert.Equal(T, "map[string]string{\"foo\":\"F00\"}", ref.Cast(map[string]string{"foo": "F00"}))
 	})
 
+	T.Run("Cast/JSON", func(T *testing.T) {
+		ref := params.Reference{}
+		assert.Equal(T, `{"foo":"F00"}`, ref.Cast(map[string]interface{}{"foo": "F00"}))
+		assert.Equal(T, `[1,"two"]`, ref.Cast([]interface{}{1, "two"}))
+	})
+
 	T.Run("Value/Array", func(T *testing.T) {
 		ref := params.Reference{
 			Result: &contract.OperationResult{
@@ -251,6 +268,81 @@ func Test_Reference(T *testing.T) {
 	})
 }
 
+func Test_ReferenceParts(T *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost/pets?limit=10", nil)
+
+	result := &contract.OperationResult{
+		HTTPRequest: req,
+		HTTPResponse: &http.Response{
+			StatusCode: 201,
+			Header: http.Header{
+				"Location":   []string{"/pets/42"},
+				"Set-Cookie": []string{"sid=s3ss10n; Path=/"},
+			},
+		},
+		RequestBytes:   []byte(`{"name": "rex"}`),
+		ResponseBytes:  []byte(`{"status": "available"}`),
+		PathParameters: map[string]string{"petId": "42"},
+	}
+
+	ref := func(part string, selector string) string {
+		return params.Reference{
+			Result:   result,
+			Part:     part,
+			Selector: selector,
+			Log:      log.NewPlain(0),
+		}.Value()()
+	}
+
+	T.Run("SplitPart", func(T *testing.T) {
+		part, selector := params.SplitPart("response", ".headers['X-Rate-Limit']")
+		assert.Equal(T, params.PartResponseHeaders, part)
+		assert.Equal(T, "['X-Rate-Limit']", selector)
+
+		part, selector = params.SplitPart("response", ".statuses[0]")
+		assert.Equal(T, params.PartResponseBody, part)
+		assert.Equal(T, ".statuses[0]", selector)
+
+		part, selector = params.SplitPart("request", ".path.petId")
+		assert.Equal(T, params.PartRequestPath, part)
+		assert.Equal(T, ".petId", selector)
+	})
+
+	T.Run("Response", func(T *testing.T) {
+		assert.Equal(T, "available", ref(params.PartResponseBody, ".status"))
+		assert.Equal(T, "201", ref(params.PartResponseStatus, ""))
+		assert.Equal(T, "/pets/42", ref(params.PartResponseHeaders, "[Location]"))
+		assert.Equal(T, "/pets/42", ref(params.PartResponseHeaders, ".location"))
+		assert.Equal(T, "s3ss10n", ref(params.PartResponseCookies, "['sid']"))
+	})
+
+	T.Run("Request", func(T *testing.T) {
+		assert.Equal(T, "rex", ref(params.PartRequestBody, ".name"))
+		assert.Equal(T, "10", ref(params.PartRequestQuery, ".limit"))
+		assert.Equal(T, "42", ref(params.PartRequestPath, "[petId]"))
+	})
+
+	T.Run("Request/Form", func(T *testing.T) {
+		result.RequestBytes = []byte("name=tom&status=sold")
+		assert.Equal(T, "tom", ref(params.PartRequestBody, ".name"))
+	})
+
+	T.Run("Missing", func(T *testing.T) {
+		for part, selector := range map[string]string{
+			params.PartResponseBody:    ".name",
+			params.PartResponseHeaders: "[X-Rate-Limit]",
+			params.PartResponseCookies: "[token]",
+			params.PartRequestQuery:    ".offset",
+			params.PartRequestPath:     "[ownerId]",
+		} {
+			T.Run(part, func(T *testing.T) {
+				defer unpanic(T, "Reference has panicked.\nSee the error message reported above for details.")
+				ref(part, selector)
+			})
+		}
+	})
+}
+
 func Test_NoAccess(T *testing.T) {
 	defer unpanic(T, "NoAccess has panicked.\nSee the error message reported above for details.")
 
@@ -0,0 +1,48 @@
+package params
+
+import (
+	"strings"
+)
+
+// scanner keeps the position of a recursive descent parser in it's source
+// and provides the character level operations shared by the parsers.
+type scanner struct {
+	Src string
+	Pos int
+}
+
+// EOF tells whether the whole source has been parsed.
+func (s *scanner) EOF() bool {
+	return s.Pos >= len(s.Src)
+}
+
+// Peek returns the current character or 0 when there are no more.
+func (s *scanner) Peek() byte {
+	if s.EOF() {
+		return 0
+	}
+
+	return s.Src[s.Pos]
+}
+
+// Next tells whether the unparsed source starts with prefix.
+func (s *scanner) Next(prefix string) bool {
+	return strings.HasPrefix(s.Src[s.Pos:], prefix)
+}
+
+// Skip skips prefix when the unparsed source starts with it.
+func (s *scanner) Skip(prefix string) bool {
+	if s.Next(prefix) {
+		s.Pos += len(prefix)
+		return true
+	}
+
+	return false
+}
+
+// SkipSpace skips blank characters.
+func (s *scanner) SkipSpace() {
+	for !s.EOF() && strings.IndexByte(" \t\n\r", s.Peek()) >= 0 {
+		s.Pos++
+	}
+}
@@ -0,0 +1,461 @@
+package params
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Template is a parameter value which mixes literal text, references
+// and function calls, like "Bearer #login.response.token"
+// or "$base64(#login.request.body.user:#login.request.body.password)".
+// "$$" is a literal "$" in templates with function calls, others are taken as is,
+// so values like "pa$$word" stay intact.
+type Template struct {
+	Source string
+	Parts  []TemplatePart
+}
+
+// TemplatePart is either a literal text, a reference or a function call.
+type TemplatePart struct {
+	Text string
+	Ref  string
+	Call *TemplateCall
+}
+
+// TemplateCall is a function call in a template, like "$upper(#op.response.name)".
+// Function arguments are templates themselves.
+type TemplateCall struct {
+	Name string
+	Args []*Template
+}
+
+// TemplateResolver creates access functions for references found in templates.
+// It returns false when the reference is not a real one and should be used as a literal text.
+type TemplateResolver func(ref string) (contract.ParameterAccess, bool, error)
+
+// ParseTemplate parses a parameter value template.
+func ParseTemplate(src string) (*Template, error) {
+	tpl, err := parseTemplate(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if tpl.Calls() {
+		tpl.Unescape()
+	}
+
+	return tpl, nil
+}
+
+// parseTemplate parses a template keeping the "$$" escapes in its text.
+func parseTemplate(src string) (*Template, error) {
+	p := &templateParser{scanner{Src: src}}
+	return p.Template("")
+}
+
+// Unescape replaces the "$$" escapes with "$" in the template text & function arguments.
+func (tpl *Template) Unescape() {
+	for i, part := range tpl.Parts {
+		tpl.Parts[i].Text = strings.Replace(part.Text, "$$", "$", -1)
+
+		if part.Call != nil {
+			for _, arg := range part.Call.Args {
+				arg.Unescape()
+			}
+		}
+	}
+}
+
+// Literal tells whether the template consists of literal text only.
+func (tpl *Template) Literal() bool {
+	for _, part := range tpl.Parts {
+		if part.Ref != "" || part.Call != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Calls tells whether the template contains function calls.
+func (tpl *Template) Calls() bool {
+	for _, part := range tpl.Parts {
+		if part.Call != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Text returns the literal text of the template.
+func (tpl *Template) Text() string {
+	res := ""
+	for _, part := range tpl.Parts {
+		res += part.Text
+	}
+
+	return res
+}
+
+// Compile creates a parameter access function which computes the template value.
+// References are resolved with resolve, function calls are checked against TemplateFunctions.
+func (tpl *Template) Compile(resolve TemplateResolver, log contract.Logger) (contract.ParameterAccess, error) {
+	vs := []contract.ParameterAccess{}
+
+	for _, part := range tpl.Parts {
+		switch {
+		case part.Call != nil:
+			v, err := part.Call.Compile(resolve, log)
+			if err != nil {
+				return nil, err
+			}
+
+			vs = append(vs, v)
+
+		case part.Ref != "":
+			v, ok, err := resolve(part.Ref)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				v = Value(part.Ref)
+			}
+
+			vs = append(vs, v)
+
+		default:
+			vs = append(vs, Value(part.Text))
+		}
+	}
+
+	if len(vs) == 1 {
+		return vs[0], nil
+	}
+
+	return Concat(vs...), nil
+}
+
+// Compile creates a parameter access function which calls the function.
+func (call *TemplateCall) Compile(resolve TemplateResolver, log contract.Logger) (contract.ParameterAccess, error) {
+	fn, ok := TemplateFunctions[call.Name]
+	if !ok {
+		return nil, errors.NotFound("Function", call.Name, nil)
+	}
+
+	if len(call.Args) < fn.MinArgs || (fn.MaxArgs >= 0 && len(call.Args) > fn.MaxArgs) {
+		return nil, errors.Oops(fmt.Sprintf("The function '%s' takes %s arguments, %d given.", call.Name, fn.Arity(), len(call.Args)), nil)
+	}
+
+	args := []contract.ParameterAccess{}
+	for _, arg := range call.Args {
+		v, err := arg.Compile(resolve, log)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	return Call(call.Name, fn.F, args, log), nil
+}
+
+// Expression creates a parameter access function from a value which may contain
+// function calls, like "$uuid()" or "order-$sequence(orders)". References are available
+// in scripts only, so here they are literal text. Invalid values are reported.
+func Expression(v string, log contract.Logger) contract.ParameterAccess {
+	literal := func(ref string) (contract.ParameterAccess, bool, error) {
+		return nil, false, nil
+	}
+
+	tpl, err := ParseTemplate(v)
+	if err != nil {
+		errors.Report(err, "Expression", log)
+	}
+
+	if !tpl.Calls() {
+		return Value(v)
+	}
+
+	access, err := tpl.Compile(literal, log)
+	if err != nil {
+		errors.Report(err, "Expression", log)
+	}
+
+	return access
+}
+
+// templateParser is a recursive descent parser for templates.
+type templateParser struct {
+	scanner
+}
+
+// Error creates a syntax error at the current position.
+func (p *templateParser) Error(details string) error {
+	return errors.Oops(fmt.Sprintf("Cannot parse the template '%s' at position %d: %s.", p.Src, p.Pos, details), nil)
+}
+
+// Template parses a template until the end of the source or one of the stop characters.
+func (p *templateParser) Template(stops string) (*Template, error) {
+	start := p.Pos
+	tpl := &Template{}
+	text := strings.Builder{}
+
+	flush := func() {
+		if text.Len() > 0 {
+			tpl.Parts = append(tpl.Parts, TemplatePart{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for !p.EOF() {
+		c := p.Peek()
+
+		if strings.IndexByte(stops, c) >= 0 {
+			break
+		}
+
+		if p.Next("$$") {
+			text.WriteString("$$")
+			p.Pos += 2
+			continue
+		}
+
+		if c == '$' {
+			if name := p.CallName(); name != "" {
+				flush()
+
+				call, err := p.Call(name)
+				if err != nil {
+					return nil, err
+				}
+
+				tpl.Parts = append(tpl.Parts, TemplatePart{Call: call})
+				continue
+			}
+		}
+
+		if c == '#' {
+			if ref := p.Ref(); ref != "" {
+				flush()
+				tpl.Parts = append(tpl.Parts, TemplatePart{Ref: ref})
+				continue
+			}
+		}
+
+		text.WriteByte(c)
+		p.Pos++
+	}
+
+	flush()
+	tpl.Source = p.Src[start:p.Pos]
+
+	return tpl, nil
+}
+
+// CallName returns the function name if a function call starts at the current position.
+func (p *templateParser) CallName() string {
+	m := regexp.MustCompile(`^\$([A-Za-z_]\w*)\(`).FindStringSubmatch(p.Src[p.Pos:])
+	if m == nil {
+		return ""
+	}
+
+	return m[1]
+}
+
+// Call parses a function call, like "$replace(#op.response.name, ' ', '_')".
+// Arguments are either quoted or bare templates, the latter are trimmed.
+func (p *templateParser) Call(name string) (*TemplateCall, error) {
+	p.Pos += len(name) + 2
+	call := &TemplateCall{Name: name}
+
+	p.SkipSpace()
+	if p.Peek() == ')' {
+		p.Pos++
+		return call, nil
+	}
+
+	for {
+		p.SkipSpace()
+
+		var arg *Template
+		var err error
+
+		if c := p.Peek(); c == '\'' || c == '"' {
+			arg, err = p.Quoted()
+		} else {
+			arg, err = p.Template(",)")
+			arg.Trim()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		call.Args = append(call.Args, arg)
+		p.SkipSpace()
+
+		switch p.Peek() {
+		case ')':
+			p.Pos++
+			return call, nil
+
+		case ',':
+			p.Pos++
+
+		default:
+			return nil, p.Error("',' or ')' is expected")
+		}
+	}
+}
+
+// Quoted parses a quoted function argument. Quotes may be escaped with "\".
+func (p *templateParser) Quoted() (*Template, error) {
+	quote := p.Peek()
+	p.Pos++
+
+	src := strings.Builder{}
+
+	for !p.EOF() {
+		c := p.Peek()
+		p.Pos++
+
+		if c == quote {
+			return parseTemplate(src.String())
+		}
+
+		if c == '\\' && !p.EOF() {
+			c = p.Peek()
+			p.Pos++
+		}
+
+		src.WriteByte(c)
+	}
+
+	return nil, p.Error("a string is not terminated")
+}
+
+// Ref returns a reference if one starts at the current position.
+// References are "#name" or "#name[N]" followed by ".field", "..field", ".*"
+// and bracketed segments. Validity of the selectors is checked later, when resolving.
+func (p *templateParser) Ref() string {
+	m := regexp.MustCompile(`^#[A-Za-z_]\w*(\[(\d+|\*)\])?`).FindString(p.Src[p.Pos:])
+	if m == "" {
+		return ""
+	}
+
+	start := p.Pos
+	p.Pos += len(m)
+
+	for !p.EOF() {
+		if p.Next("..") && p.SegmentAt(p.Pos+2) {
+			p.Pos += 2
+		} else if p.Peek() == '.' && p.SegmentAt(p.Pos+1) && p.Src[p.Pos+1] != '[' {
+			p.Pos++
+		} else if p.Peek() != '[' {
+			break
+		}
+
+		if !p.Segment() {
+			break
+		}
+	}
+
+	return p.Src[start:p.Pos]
+}
+
+// SegmentAt tells whether a name, a wildcard or a bracket starts at pos.
+func (p *templateParser) SegmentAt(pos int) bool {
+	if pos >= len(p.Src) {
+		return false
+	}
+
+	c := p.Src[pos]
+	return c == '*' || c == '[' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// Segment skips a name, a wildcard or a bracketed segment.
+// It returns false when a bracket is not closed.
+func (p *templateParser) Segment() bool {
+	switch p.Peek() {
+	case '*':
+		p.Pos++
+
+	case '[':
+		end := p.BracketEnd(p.Pos)
+		if end < 0 {
+			return false
+		}
+
+		p.Pos = end + 1
+
+	default:
+		for !p.EOF() && p.SegmentAt(p.Pos) && p.Peek() != '*' && p.Peek() != '[' {
+			p.Pos++
+		}
+	}
+
+	return true
+}
+
+// BracketEnd returns the position of the bracket closing the one at pos,
+// skipping nested brackets & quoted strings, or -1 when there is none.
+func (p *templateParser) BracketEnd(pos int) int {
+	depth := 0
+	quote := byte(0)
+
+	for i := pos; i < len(p.Src); i++ {
+		c := p.Src[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+
+		case c == '\'' || c == '"':
+			quote = c
+
+		case c == '[':
+			depth++
+
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Trim removes leading & trailing blanks from the template text.
+func (tpl *Template) Trim() {
+	if tpl == nil || len(tpl.Parts) == 0 {
+		return
+	}
+
+	first := &tpl.Parts[0]
+	first.Text = strings.TrimLeft(first.Text, " \t\n\r")
+
+	last := &tpl.Parts[len(tpl.Parts)-1]
+	last.Text = strings.TrimRight(last.Text, " \t\n\r")
+
+	parts := []TemplatePart{}
+	for _, part := range tpl.Parts {
+		if part.Text != "" || part.Ref != "" || part.Call != nil {
+			parts = append(parts, part)
+		}
+	}
+
+	tpl.Parts = parts
+}
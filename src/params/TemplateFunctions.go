@@ -0,0 +1,262 @@
+package params
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// TemplateFunc is a function which may be called from templates.
+// It takes the argument values and returns the result.
+type TemplateFunc func(args []string) (string, error)
+
+// TemplateFunction describes a template function and it's arity.
+// MaxArgs is -1 for variadic functions.
+type TemplateFunction struct {
+	MinArgs int
+	MaxArgs int
+	F       TemplateFunc
+}
+
+// Arity returns a human readable number of the function arguments.
+func (fn TemplateFunction) Arity() string {
+	if fn.MinArgs == fn.MaxArgs {
+		return strconv.Itoa(fn.MinArgs)
+	}
+
+	if fn.MaxArgs < 0 {
+		return fmt.Sprintf("%d or more", fn.MinArgs)
+	}
+
+	return fmt.Sprintf("%d to %d", fn.MinArgs, fn.MaxArgs)
+}
+
+// TemplateFunctions is the library of functions available in templates.
+var TemplateFunctions = map[string]TemplateFunction{
+	"base64": {1, 1, func(args []string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+	}},
+
+	"urlencode": {1, 1, func(args []string) (string, error) {
+		return url.QueryEscape(args[0]), nil
+	}},
+
+	"upper": {1, 1, func(args []string) (string, error) {
+		return strings.ToUpper(args[0]), nil
+	}},
+
+	"lower": {1, 1, func(args []string) (string, error) {
+		return strings.ToLower(args[0]), nil
+	}},
+
+	"sha256": {1, 1, func(args []string) (string, error) {
+		sum := sha256.Sum256([]byte(args[0]))
+		return hex.EncodeToString(sum[:]), nil
+	}},
+
+	"hmac": {2, 3, HMAC},
+
+	"jsonEncode": {1, 1, func(args []string) (string, error) {
+		res, err := json.Marshal(args[0])
+		return string(res), err
+	}},
+
+	"substr": {2, 3, Substr},
+
+	"replace": {3, 3, func(args []string) (string, error) {
+		return strings.Replace(args[0], args[1], args[2], -1), nil
+	}},
+
+	"dateAdd": {2, 3, func(args []string) (string, error) {
+		t, err := ParseDate(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		d, err := ParseDuration(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		layout := "RFC3339"
+		if len(args) > 2 {
+			layout = args[2]
+		}
+
+		return FormatDate(t.Add(d), layout), nil
+	}},
+
+	"dateFormat": {2, 2, func(args []string) (string, error) {
+		t, err := ParseDate(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		return FormatDate(t, args[1]), nil
+	}},
+
+	"uuid": {0, 0, func(args []string) (string, error) {
+		return UUID(), nil
+	}},
+
+	"now":          {0, 2, Now},
+	"randomInt":    {2, 2, GenerateInt},
+	"randomString": {1, 2, GenerateString},
+	"email":        {0, 1, GenerateEmail},
+	"sequence":     {1, 2, GenerateSequence},
+}
+
+// HMAC computes a hex-encoded HMAC of a message (args[1]) with a key (args[0]).
+// The hash algorithm (args[2]) is one of sha1, sha256 (the default) or sha512.
+func HMAC(args []string) (string, error) {
+	hashes := map[string]func() hash.Hash{
+		"sha1":   sha1.New,
+		"sha256": sha256.New,
+		"sha512": sha512.New,
+	}
+
+	alg := "sha256"
+	if len(args) > 2 {
+		alg = strings.ToLower(args[2])
+	}
+
+	h, ok := hashes[alg]
+	if !ok {
+		return "", errors.NotFound("Hash algorithm", alg, nil)
+	}
+
+	mac := hmac.New(h, []byte(args[0]))
+	mac.Write([]byte(args[1]))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Substr returns a substring of args[0] starting at the args[1] character.
+// The optional args[2] limits the substring length. Negative starts count from the end.
+func Substr(args []string) (string, error) {
+	s := []rune(args[0])
+
+	start, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", errors.Oops("The start '"+args[1]+"' is not an integer.", err)
+	}
+
+	if start < 0 {
+		start += len(s)
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if start > len(s) {
+		start = len(s)
+	}
+
+	end := len(s)
+	if len(args) > 2 {
+		length, err := strconv.Atoi(args[2])
+		if err != nil {
+			return "", errors.Oops("The length '"+args[2]+"' is not an integer.", err)
+		}
+
+		if start+length < end {
+			end = start + length
+		}
+	}
+
+	if end < start {
+		end = start
+	}
+
+	return string(s[start:end]), nil
+}
+
+// DateLayouts are the named date layouts usable in templates.
+// Other layouts are Go time layouts, like "2006-01-02".
+var DateLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"ANSIC":       time.ANSIC,
+	"Kitchen":     time.Kitchen,
+	"Date":        "2006-01-02",
+	"Time":        "15:04:05",
+	"DateTime":    "2006-01-02 15:04:05",
+}
+
+// FormatDate formats t with a named or a Go layout.
+// "Unix" and "UnixMilli" produce timestamps.
+func FormatDate(t time.Time, layout string) string {
+	switch layout {
+	case "Unix":
+		return strconv.FormatInt(t.Unix(), 10)
+
+	case "UnixMilli":
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	if l, ok := DateLayouts[layout]; ok {
+		layout = l
+	}
+
+	return t.Format(layout)
+}
+
+// ParseDate parses a date in one of the named layouts or a Unix timestamp.
+func ParseDate(v string) (time.Time, error) {
+	for _, name := range []string{"RFC3339Nano", "RFC3339", "DateTime", "Date", "RFC1123Z", "RFC1123", "RFC850", "ANSIC"} {
+		if t, err := time.Parse(DateLayouts[name], v); err == nil {
+			return t, nil
+		}
+	}
+
+	if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(ts, 0).UTC(), nil
+	}
+
+	return time.Time{}, errors.Oops("Cannot parse the date '"+v+"'.", nil)
+}
+
+// ParseDuration parses a Go duration which may also contain days, like "-7d" or "1d12h".
+func ParseDuration(v string) (time.Duration, error) {
+	m := regexp.MustCompile(`^([-+]?)(\d+)d(.*)$`).FindStringSubmatch(v)
+	if m == nil {
+		return time.ParseDuration(v)
+	}
+
+	days, _ := strconv.Atoi(m[2])
+	d := time.Duration(days) * 24 * time.Hour
+
+	if m[3] != "" {
+		rest, err := time.ParseDuration(m[3])
+		if err != nil {
+			return 0, err
+		}
+
+		d += rest
+	}
+
+	if m[1] == "-" {
+		d = -d
+	}
+
+	return d, nil
+}
@@ -0,0 +1,134 @@
+package params_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+func Test_ParseTemplate(T *testing.T) {
+	refs := func(src string) []string {
+		tpl, err := params.ParseTemplate(src)
+		assert.Nil(T, err)
+
+		res := []string{}
+		for _, part := range tpl.Parts {
+			if part.Ref != "" {
+				res = append(res, part.Ref)
+			}
+		}
+
+		return res
+	}
+
+	T.Run("Literal", func(T *testing.T) {
+		tpl, err := params.ParseTemplate("just a $tring, costs $$5")
+		assert.Nil(T, err)
+		assert.True(T, tpl.Literal())
+		assert.Equal(T, "just a $tring, costs $$5", tpl.Text())
+	})
+
+	T.Run("Escapes", func(T *testing.T) {
+		tpl, err := params.ParseTemplate("$$upper(x) costs $$5: $upper('$$5')")
+		assert.Nil(T, err)
+		assert.Equal(T, "$upper(x) costs $5: ", tpl.Text())
+		assert.Equal(T, "$5", tpl.Parts[1].Call.Args[0].Text())
+	})
+
+	T.Run("References", func(T *testing.T) {
+		assert.Equal(T, []string{"#login.response.token"}, refs("Bearer #login.response.token"))
+		assert.Equal(T, []string{"#up.response.id"}, refs("/files/#up.response.id/content"))
+		assert.Equal(T, []string{"#op.response.id"}, refs("Done #op.response.id."))
+		assert.Equal(T, []string{"#a.response[0]['x-id']", "#b.response.headers[Location]"}, refs("#a.response[0]['x-id']-#b.response.headers[Location]"))
+		assert.Equal(T, []string{"#list.response[?(@.name=='a b')].id"}, refs("#list.response[?(@.name=='a b')].id"))
+		assert.Equal(T, []string{"#getPet[*].response", "#item"}, refs("#getPet[*].response #item"))
+		assert.Equal(T, []string{}, refs("issue #42"))
+	})
+
+	T.Run("Calls", func(T *testing.T) {
+		tpl, err := params.ParseTemplate("Basic $base64( #u.response.name:#u.response.pwd , x)!")
+		assert.Nil(T, err)
+		assert.Len(T, tpl.Parts, 3)
+		assert.Equal(T, "Basic ", tpl.Parts[0].Text)
+		assert.Equal(T, "base64", tpl.Parts[1].Call.Name)
+		assert.Len(T, tpl.Parts[1].Call.Args, 2)
+		assert.Equal(T, "#u.response.name", tpl.Parts[1].Call.Args[0].Parts[0].Ref)
+		assert.Equal(T, "x", tpl.Parts[1].Call.Args[1].Text())
+		assert.Equal(T, "!", tpl.Parts[2].Text)
+	})
+
+	T.Run("Unterminated call", func(T *testing.T) {
+		_, err := params.ParseTemplate("$upper(foo")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Unterminated string", func(T *testing.T) {
+		_, err := params.ParseTemplate("$upper('foo)")
+		assert.NotNil(T, err)
+	})
+}
+
+func Test_TemplateCompile(T *testing.T) {
+	resolve := func(ref string) (contract.ParameterAccess, bool, error) {
+		if strings.HasPrefix(ref, "#op.") {
+			return params.Value(strings.TrimPrefix(ref, "#op.response.")), true, nil
+		}
+
+		return nil, false, nil
+	}
+
+	compile := func(src string) (string, error) {
+		tpl, err := params.ParseTemplate(src)
+		if err != nil {
+			return "", err
+		}
+
+		access, err := tpl.Compile(resolve, log.NewPlain(0))
+		if err != nil {
+			return "", err
+		}
+
+		return access(), nil
+	}
+
+	cases := map[string]string{
+		"Bearer #op.response.token":             "Bearer token",
+		"/files/#op.response.id/content":        "/files/id/content",
+		"color #fff":                            "color #fff",
+		"$upper(#op.response.name) $lower(ABC)": "NAME abc",
+		"$base64(user:pass)":                    "dXNlcjpwYXNz",
+		"$urlencode('a b&c')":                   "a+b%26c",
+		"$sha256(abc)":                          "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"$hmac(key, 'The quick brown fox jumps over the lazy dog')": "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		"$jsonEncode('say \"hi\"')":                                 `"say \"hi\""`,
+		"$substr(abcdef, 1, 3) $substr(abcdef, -2)":                 "bcd ef",
+		"$replace(a-b-c, -, _)":                                     "a_b_c",
+		"$upper($replace(#op.response.x, x, y))":                    "Y",
+		"$dateAdd(2024-01-31T00:00:00Z, 1d12h)":                     "2024-02-01T12:00:00Z",
+		"$dateAdd(2024-01-31, -7d, Date)":                           "2024-01-24",
+		"$dateFormat(1700000000, RFC1123)":                          "Tue, 14 Nov 2023 22:13:20 UTC",
+	}
+
+	for src, expected := range cases {
+		src, expected := src, expected
+		T.Run(src, func(T *testing.T) {
+			actual, err := compile(src)
+			assert.Nil(T, err)
+			assert.Equal(T, expected, actual)
+		})
+	}
+
+	T.Run("Unknown function", func(T *testing.T) {
+		_, err := compile("$nope(1)")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Arity", func(T *testing.T) {
+		_, err := compile("$replace(a, b)")
+		assert.NotNil(T, err)
+	})
+}
@@ -15,6 +15,7 @@ type URLParameters struct {
 	*Set
 
 	Path string
+	Used map[string]string
 }
 
 // URL creates a new URLParameters instance.
@@ -23,6 +24,7 @@ func URL(path string, log contract.Logger) *URLParameters {
 		EntityTrait: contract.Entity(log),
 		Set:         NewSet("URL"),
 		Path:        path,
+		Used:        map[string]string{},
 	}
 
 	p.Require(KeyHost)
@@ -39,6 +41,10 @@ func (params URLParameters) String() string {
 
 	tpl := "{" + KeyHost + "}" + params.Path
 
+	for k := range params.Used {
+		delete(params.Used, k)
+	}
+
 	for p := range params.Iterate() {
 		rx := regexp.MustCompile("\\{" + p.N + "\\}")
 
@@ -46,6 +52,7 @@ func (params URLParameters) String() string {
 			v := p.V()
 			if v != "" {
 				params.Log.UsingParameterExample(p.N, "path", p.Source, v)
+				params.Used[p.N] = v
 				tpl = string(rx.ReplaceAll([]byte(tpl), []byte(v)))
 			}
 		}
@@ -53,3 +60,14 @@ func (params URLParameters) String() string {
 
 	return tpl
 }
+
+// Values returns the parameter values used to build the last URL string.
+// Values may be generated, so they are kept rather than computed once again.
+func (params URLParameters) Values() map[string]string {
+	res := map[string]string{}
+	for k, v := range params.Used {
+		res[k] = v
+	}
+
+	return res
+}
@@ -0,0 +1,326 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Cassettes, when set, makes requests either record their exchanges
+// into the cassette files or replay them from there instead of sending.
+var Cassettes *CassetteDeck
+
+// Exchange is a recorded request & response pair.
+type Exchange struct {
+	Node     string           `json:"node"`
+	Method   string           `json:"method"`
+	URL      string           `json:"url"`
+	Body     string           `json:"body,omitempty"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedResponse is a recorded HTTP response.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Matches tells whether the exchange has been recorded for the same request.
+func (ex Exchange) Matches(ex2 Exchange) bool {
+	return ex.Node == ex2.Node && ex.Method == ex2.Method && ex.URL == ex2.URL && ex.Body == ex2.Body
+}
+
+// CassetteDeck records & replays request exchanges. Exchanges are stored
+// in the Dir directory, in a JSON file per execution node, and are identified
+// by the method, URL & normalized body of their requests. When a request is repeated,
+// like when polling, the exchanges are replayed in the order they were recorded.
+type CassetteDeck struct {
+	Dir       string
+	Recording bool
+
+	mutex     sync.Mutex
+	cassettes map[string][]Exchange
+	played    map[string]int
+}
+
+// NewCassetteDeck creates a new CassetteDeck instance
+// which either records or replays exchanges.
+func NewCassetteDeck(dir string, recording bool) *CassetteDeck {
+	return &CassetteDeck{
+		Dir:       dir,
+		Recording: recording,
+		cassettes: map[string][]Exchange{},
+		played:    map[string]int{},
+	}
+}
+
+var cassetteNameRx = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Path returns the path to the cassette file of a node.
+func (deck *CassetteDeck) Path(node string) string {
+	name := cassetteNameRx.ReplaceAllString(node, "_")
+	if name == "" {
+		name = "requests"
+	}
+
+	return filepath.Join(deck.Dir, name+".json")
+}
+
+// NewExchange creates an Exchange for a request made by a node.
+// Query parameters are sorted, JSON & form bodies are normalized,
+// so the same requests match regardless of formatting.
+// The credentials of the SecurityCassette requests are redacted.
+func NewExchange(node string, req *http.Request, body []byte) Exchange {
+	u := *req.URL
+	u.RawQuery = u.Query().Encode()
+
+	ex := Exchange{
+		Node:   node,
+		Method: req.Method,
+		URL:    u.String(),
+		Body:   NormalizeBody(req.Header.Get("Content-Type"), body),
+	}
+
+	if node == SecurityCassette {
+		ex.Body = Redact(req.Header.Get("Content-Type"), ex.Body)
+	}
+
+	return ex
+}
+
+// NormalizeBody re-encodes JSON & form bodies in a canonical form.
+func NormalizeBody(CT string, body []byte) string {
+	mt, _, _ := mime.ParseMediaType(CT)
+
+	if mt == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			return form.Encode()
+		}
+	}
+
+	var v interface{}
+	if json.Unmarshal(body, &v) == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			return string(normalized)
+		}
+	}
+
+	return string(body)
+}
+
+// Credentials are the fields of the security cassette request & response bodies
+// which are never stored, like passwords & tokens. Their values are replaced with
+// a placeholder, so the requests are matched by their presence rather than values.
+var Credentials = []string{"password", "client_secret", "client_assertion", "code", "code_verifier", "refresh_token", "access_token"}
+
+// Redacted is the placeholder of the credential values in the cassettes.
+const Redacted = "REDACTED"
+
+// Redact replaces the Credentials values of a form or a JSON object body with Redacted.
+// ID tokens are removed, as their signatures can't be verified without the actual value.
+func Redact(CT string, body string) string {
+	mt, _, _ := mime.ParseMediaType(CT)
+
+	if mt == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(body); err == nil {
+			for _, field := range Credentials {
+				if _, ok := form[field]; ok {
+					form.Set(field, Redacted)
+				}
+			}
+
+			return form.Encode()
+		}
+	}
+
+	var obj map[string]interface{}
+	if json.Unmarshal([]byte(body), &obj) == nil && obj != nil {
+		for _, field := range Credentials {
+			if _, ok := obj[field]; ok {
+				obj[field] = Redacted
+			}
+		}
+
+		delete(obj, "id_token")
+
+		if redacted, err := json.Marshal(obj); err == nil {
+			return string(redacted)
+		}
+	}
+
+	return body
+}
+
+// Record stores an exchange in the node's cassette file.
+// The credentials of the SecurityCassette exchanges are redacted.
+func (deck *CassetteDeck) Record(node string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	ex := NewExchange(node, req, reqBody)
+	ex.Response = RecordedResponse{
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   string(respBody),
+	}
+
+	if node == SecurityCassette {
+		ex.Response.Body = Redact(resp.Header.Get("Content-Type"), ex.Response.Body)
+	}
+
+	deck.mutex.Lock()
+	defer deck.mutex.Unlock()
+
+	deck.cassettes[node] = append(deck.cassettes[node], ex)
+
+	data := &bytes.Buffer{}
+	enc := json.NewEncoder(data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(deck.cassettes[node])
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(deck.Dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(deck.Path(node), data.Bytes(), 0644)
+}
+
+// Replay finds the recorded response to a request made by a node.
+func (deck *CassetteDeck) Replay(node string, req *http.Request, reqBody []byte) (*http.Response, error) {
+	ex := NewExchange(node, req, reqBody)
+
+	deck.mutex.Lock()
+	defer deck.mutex.Unlock()
+
+	cassette, err := deck.Load(node)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeated requests get the responses in the order they were recorded.
+	key := fmt.Sprintf("%s %s %s %s", ex.Node, ex.Method, ex.URL, ex.Body)
+	n := deck.played[key]
+
+	for _, recorded := range cassette {
+		if !recorded.Matches(ex) {
+			continue
+		}
+
+		if n > 0 {
+			n--
+			continue
+		}
+
+		deck.played[key]++
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.Status, http.StatusText(recorded.Response.Status)),
+			StatusCode:    recorded.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Response.Header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(recorded.Response.Body))),
+			ContentLength: int64(len(recorded.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.Oops(fmt.Sprintf("The %s cassette has no recorded response to %s %s.", deck.Path(node), ex.Method, ex.URL), nil)
+}
+
+// Load reads the node's cassette file, once.
+func (deck *CassetteDeck) Load(node string) ([]Exchange, error) {
+	if cassette, ok := deck.cassettes[node]; ok {
+		return cassette, nil
+	}
+
+	cassette := []Exchange{}
+
+	data, err := ioutil.ReadFile(deck.Path(node))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		err = json.Unmarshal(data, &cassette)
+		if err != nil {
+			return nil, errors.Oops("Cannot parse the cassette file '"+deck.Path(node)+"'.", err)
+		}
+	}
+
+	deck.cassettes[node] = cassette
+
+	return cassette, nil
+}
+
+// SecurityCassette is the cassette of the requests securities make on their own,
+// like the token requests.
+const SecurityCassette = "security"
+
+// Send sends a request which is not an operation one, like a token request,
+// and reads the response body. When the cassettes are in use, the exchange is recorded
+// into or replayed from the node's cassette.
+func Send(node string, client *http.Client, req *http.Request, reqBody []byte) (*http.Response, []byte, error) {
+	var resp *http.Response
+	var err error
+
+	if Cassettes != nil && !Cassettes.Recording {
+		resp, err = Cassettes.Replay(node, req, reqBody)
+	} else {
+		resp, err = client.Do(req)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if Cassettes != nil && Cassettes.Recording {
+		err = Cassettes.Record(node, req, reqBody, resp, respBody)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return resp, respBody, nil
+}
+
+type nodeIDKey struct{}
+
+// NodeID is a request enrichment which tells which execution node has made
+// the request, so the cassettes can tell apart the same requests of different nodes.
+type NodeID string
+
+// Enrich stores the node ID in the request context.
+func (id NodeID) Enrich(req *http.Request, log contract.Logger) {
+	*req = *req.WithContext(context.WithValue(req.Context(), nodeIDKey{}, string(id)))
+}
+
+// RequestNodeID returns the ID of the node which has made the request.
+func RequestNodeID(req *http.Request) string {
+	id, _ := req.Context().Value(nodeIDKey{}).(string)
+	return id
+}
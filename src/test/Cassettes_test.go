@@ -0,0 +1,91 @@
+package test_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+func Test_Cassettes(T *testing.T) {
+	dir, _ := ioutil.TempDir("", "oasis")
+	defer os.RemoveAll(dir)
+
+	request := func(node string, URL string, body string) *http.Request {
+		req, _ := http.NewRequest("POST", URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		test.NodeID(node).Enrich(req, log.NewPlain(0))
+		return req
+	}
+
+	response := func(status int) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		}
+	}
+
+	T.Run("NodeID", func(T *testing.T) {
+		assert.Equal(T, "addPet", test.RequestNodeID(request("addPet", "http://localhost/pets", "")))
+		assert.Equal(T, "", test.RequestNodeID(&http.Request{}))
+	})
+
+	T.Run("Record", func(T *testing.T) {
+		deck := test.NewCassetteDeck(dir, true)
+
+		err := deck.Record("addPet", request("addPet", "http://localhost/pets?b=2&a=1", `{"name": "Rex", "age": 1}`), []byte(`{"name": "Rex", "age": 1}`), response(202), []byte("pending"))
+		assert.Nil(T, err)
+
+		err = deck.Record("addPet", request("addPet", "http://localhost/pets?b=2&a=1", `{"name": "Rex", "age": 1}`), []byte(`{"name": "Rex", "age": 1}`), response(201), []byte("created"))
+		assert.Nil(T, err)
+
+		_, err = os.Stat(filepath.Join(dir, "addPet.json"))
+		assert.Nil(T, err)
+	})
+
+	T.Run("Replay", func(T *testing.T) {
+		deck := test.NewCassetteDeck(dir, false)
+
+		// Differently formatted, but the same requests.
+		body := []byte(`{"age":1,"name":"Rex"}`)
+
+		resp, err := deck.Replay("addPet", request("addPet", "http://localhost/pets?a=1&b=2", string(body)), body)
+		assert.Nil(T, err)
+		assert.Equal(T, 202, resp.StatusCode)
+		assert.Equal(T, "text/plain", resp.Header.Get("Content-Type"))
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		assert.Equal(T, "pending", string(respBody))
+
+		resp, err = deck.Replay("addPet", request("addPet", "http://localhost/pets?a=1&b=2", string(body)), body)
+		assert.Nil(T, err)
+		assert.Equal(T, 201, resp.StatusCode)
+
+		_, err = deck.Replay("addPet", request("addPet", "http://localhost/pets?a=1&b=2", string(body)), body)
+		assert.NotNil(T, err)
+
+		_, err = deck.Replay("addPet", request("addPet", "http://localhost/pets", string(body)), body)
+		assert.NotNil(T, err)
+
+		_, err = deck.Replay("addCat", request("addCat", "http://localhost/pets?a=1&b=2", string(body)), body)
+		assert.NotNil(T, err)
+	})
+
+	T.Run("NormalizeBody", func(T *testing.T) {
+		assert.Equal(T, `{"a":1,"b":[true]}`, test.NormalizeBody("application/json", []byte(`{ "b": [true], "a": 1 }`)))
+		assert.Equal(T, `a=1&b=2`, test.NormalizeBody("application/x-www-form-urlencoded; charset=utf-8", []byte(`b=2&a=1`)))
+		assert.Equal(T, `plain text`, test.NormalizeBody("text/plain", []byte(`plain text`)))
+	})
+	T.Run("Redact", func(T *testing.T) {
+		form := "application/x-www-form-urlencoded"
+		assert.Equal(T, "grant_type=password&password=REDACTED&username=user", test.Redact(form, "grant_type=password&password=s3cr3t&username=user"))
+		assert.Equal(T, "grant_type=client_credentials", test.Redact(form, "grant_type=client_credentials"))
+		assert.Equal(T, `{"access_token":"REDACTED","expires_in":3600,"refresh_token":"REDACTED"}`, test.Redact("application/json", `{"access_token": "t0k3n", "refresh_token": "rt", "id_token": "a.b.c", "expires_in": 3600}`))
+		assert.Equal(T, `password=s3cr3t`, test.Redact("text/plain", `password=s3cr3t`))
+	})
+}
@@ -20,6 +20,16 @@ func Operation(
 	enrichment *[]contract.RequestEnrichment,
 	v contract.Validator,
 	log contract.Logger,
+) *contract.OperationResult {
+	return Validate(Execute(op, enrichment, log), v, log)
+}
+
+// Execute creates an operation request, enriches it with data
+// and performs it without validating the response.
+func Execute(
+	op contract.Operation,
+	enrichment *[]contract.RequestEnrichment,
+	log contract.Logger,
 ) *contract.OperationResult {
 	// Creating a request.
 	req := NewRequest(op, log)
@@ -30,9 +40,17 @@ func Operation(
 	}
 
 	// Requesting.
-	result := req.Execute()
 	//TODO: check for errors, use NullOperationResult or smth.
+	return req.Execute()
+}
 
+// Validate tests an operation result against the validator expectations
+// and reports the outcome.
+func Validate(
+	result *contract.OperationResult,
+	v contract.Validator,
+	log contract.Logger,
+) *contract.OperationResult {
 	// Testing & returning.
 	result = v.Validate(result)
 
@@ -1,16 +1,24 @@
 package test
 
 import (
+	"bytes"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
+	"time"
 
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 )
 
+// StrictRequests makes the requests which don't conform to the spec
+// fail the operation instead of being sent anyway.
+var StrictRequests = false
+
 // Request represents an operation HTTP request.
 // It contains native API objects, supplies data, tracks results and does logging.
 type Request struct {
 	contract.EntityTrait
+	Resolver    contract.DataResolver
 	HTTPRequest *http.Request
 	HTTPClient  *http.Client
 	Result      *contract.OperationResult
@@ -25,6 +33,7 @@ func NewRequest(op contract.Operation, log contract.Logger) contract.Request {
 
 	req := &Request{
 		EntityTrait: contract.Entity(log),
+		Resolver:    op.Resolve(),
 
 		HTTPRequest: httpreq,
 		HTTPClient: &http.Client{
@@ -38,6 +47,9 @@ func NewRequest(op contract.Operation, log contract.Logger) contract.Request {
 
 	req.Result.HTTPRequest = req.HTTPRequest
 
+	// Keeping the path parameters so they can be referenced later.
+	req.Result.PathParameters = op.Data().URL.Values()
+
 	return req
 }
 
@@ -49,7 +61,42 @@ func (req *Request) Enrich(en contract.RequestEnrichment) {
 // Execute executes the request.
 func (req *Request) Execute() *contract.OperationResult {
 	req.Log.Requesting(req.HTTPRequest.Method, req.HTTPRequest.URL.String())
-	response, err := req.HTTPClient.Do(req.HTTPRequest)
+
+	req.Result.Timing = contract.RequestTiming{}
+
+	// Keeping the request body so it can be referenced later.
+	req.Result.RequestBytes = nil
+	if req.HTTPRequest.Body != nil {
+		req.Result.RequestBytes, _ = ioutil.ReadAll(req.HTTPRequest.Body)
+		req.HTTPRequest.Body = ioutil.NopCloser(bytes.NewReader(req.Result.RequestBytes))
+	}
+
+	// The request is fully enriched by now, so it's checked as it is going to be sent.
+	if violations := req.Resolver.RequestViolations(req.Result); len(violations) > 0 {
+		req.Log.InvalidRequest(violations, StrictRequests)
+
+		if StrictRequests {
+			req.Result.Success = false
+			return req.Result
+		}
+	}
+
+	// The clock starts after the validation, so the timings are of the exchange only.
+	start := time.Now()
+	trace := Trace(&req.Result.Timing, start)
+	req.HTTPRequest = req.HTTPRequest.WithContext(httptrace.WithClientTrace(req.HTTPRequest.Context(), trace))
+	req.Result.HTTPRequest = req.HTTPRequest
+
+	var response *http.Response
+	var err error
+
+	node := RequestNodeID(req.HTTPRequest)
+
+	if Cassettes != nil && !Cassettes.Recording {
+		response, err = Cassettes.Replay(node, req.HTTPRequest, req.Result.RequestBytes)
+	} else {
+		response, err = req.HTTPClient.Do(req.HTTPRequest)
+	}
 
 	if err != nil {
 		req.Log.Error(err)
@@ -60,6 +107,13 @@ func (req *Request) Execute() *contract.OperationResult {
 	//TODO: this may fail on very large responses (GB++)
 	//TODO: handle the error.
 	req.Result.ResponseBytes, _ = ioutil.ReadAll(response.Body)
+	req.Result.Timing.Total = time.Since(start)
+
+	if Cassettes != nil && Cassettes.Recording {
+		err = Cassettes.Record(node, req.HTTPRequest, req.Result.RequestBytes, response, req.Result.ResponseBytes)
+	}
+
+	req.Log.RequestTiming(req.Result.Timing)
 
 	if err != nil {
 		req.Log.Error(err)
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Trace creates an httptrace.ClientTrace instance which records
+// durations of the request phases into timing.
+// The start time is used as a reference point for the time-to-first-byte value.
+// Dialers may connect to several addresses at once (like Happy Eyeballs does),
+// so connections are timed per address and only the successful one is recorded.
+func Trace(timing *contract.RequestTiming, start time.Time) *httptrace.ClientTrace {
+	var dnsStart, tlsStart time.Time
+
+	connectStarts := map[string]time.Time{}
+	lock := sync.Mutex{}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timing.DNS = time.Since(dnsStart)
+		},
+
+		ConnectStart: func(network string, addr string) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			connectStarts[network+" "+addr] = time.Now()
+		},
+
+		ConnectDone: func(network string, addr string, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err == nil {
+				timing.Connect = time.Since(connectStarts[network+" "+addr])
+			}
+		},
+
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			timing.TLS = time.Since(tlsStart)
+		},
+
+		GotFirstResponseByte: func() {
+			timing.TTFB = time.Since(start)
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package test_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+func Test_Trace(T *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	timing := contract.RequestTiming{}
+	start := time.Now()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), test.Trace(&timing, start)))
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(T, err)
+	resp.Body.Close()
+
+	assert.True(T, timing.Connect > 0)
+	assert.True(T, timing.TTFB >= 10*time.Millisecond)
+	assert.Equal(T, time.Duration(0), timing.TLS)
+}
+
+func Test_TraceConcurrentDials(T *testing.T) {
+	timing := contract.RequestTiming{}
+	trace := test.Trace(&timing, time.Now())
+
+	// The IPv6 dial starts first and fails after the IPv4 one succeeds.
+	trace.ConnectStart("tcp", "[::1]:80")
+	time.Sleep(20 * time.Millisecond)
+	trace.ConnectStart("tcp", "127.0.0.1:80")
+	trace.ConnectDone("tcp", "127.0.0.1:80", nil)
+	trace.ConnectDone("tcp", "[::1]:80", errors.New("connection refused"))
+
+	assert.True(T, timing.Connect > 0)
+	assert.True(T, timing.Connect < 20*time.Millisecond)
+}
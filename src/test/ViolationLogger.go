@@ -0,0 +1,70 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ViolationLogger is used in place of the regular logger when validating
+// someone else's traffic, like in the proxy mode. It collects the failures
+// reported by the response expectations as violations, so they can be
+// reported together & regardless of the log level.
+// Everything else is passed through to the inner logger.
+type ViolationLogger struct {
+	contract.Logger
+	Violations []contract.Violation
+}
+
+// NewViolationLogger creates a new ViolationLogger instance.
+func NewViolationLogger(inner contract.Logger) *ViolationLogger {
+	return &ViolationLogger{
+		Logger:     inner,
+		Violations: []contract.Violation{},
+	}
+}
+
+// Violation collects a violation.
+func (log *ViolationLogger) Violation(location string, msg string) {
+	log.Violations = append(log.Violations, contract.Violation{Location: location, Message: msg})
+}
+
+// Error collects an error.
+func (log *ViolationLogger) Error(err error) {
+	log.Violation("response", err.Error())
+}
+
+// NOMESSAGE collects a message.
+func (log *ViolationLogger) NOMESSAGE(msg string, args ...interface{}) {
+	log.Violation("response", strings.TrimSpace(fmt.Sprintf(msg, args...)))
+}
+
+// HeaderHasNoValue collects a missing header violation.
+func (log *ViolationLogger) HeaderHasNoValue(hdr string) {
+	log.Violation("response header "+hdr, "The header is required but is not present.")
+}
+
+// ResponseHasWrongStatus collects a status violation.
+func (log *ViolationLogger) ResponseHasWrongStatus(expectedStatus int, actualStatus int) {
+	log.Violation("response status", fmt.Sprintf("Expected %d, but got %d.", expectedStatus, actualStatus))
+}
+
+// ResponseHasWrongContentType collects a content type violation.
+func (log *ViolationLogger) ResponseHasWrongContentType(expectedCT string, actualCT string) {
+	log.Violation("response Content-Type", fmt.Sprintf("Expected '%s', but got '%s'.", expectedCT, actualCT))
+}
+
+// SchemaFail collects the schema violations. Response bodies & headers
+// are validated against the schemas named after them.
+func (log *ViolationLogger) SchemaFail(schemaName string, errors []gojsonschema.ResultError) {
+	for _, err := range errors {
+		location := schemaName
+		if err.Field() != "(root)" {
+			location += " " + err.Field()
+		}
+
+		log.Violation(location, err.Description())
+	}
+}
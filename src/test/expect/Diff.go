@@ -0,0 +1,124 @@
+package expect
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Mismatch is a difference between an expected & actual documents.
+type Mismatch struct {
+	Pointer  string
+	Expected string
+	Actual   string
+}
+
+// Pointer appends an escaped reference token to a JSON pointer (RFC 6901).
+func Pointer(pointer string, token string) string {
+	token = strings.Replace(token, "~", "~0", -1)
+	token = strings.Replace(token, "/", "~1", -1)
+
+	return pointer + "/" + token
+}
+
+// Diff compares an actual value with an expected partial document.
+// Objects must have all the expected properties, the others are ignored.
+// Arrays must have the same length, their items are compared one by one.
+// Expected strings may be parameter access functions, their values are compared
+// with the actual values cast to strings. Other scalars must be equal.
+func Diff(pointer string, expected interface{}, actual interface{}, ok bool) []Mismatch {
+	return diff(pointer, expected, actual, ok, false)
+}
+
+// DiffExact compares an actual value with an expected document.
+// Unlike Diff, it reports unexpected object properties
+// and compares strings without casting.
+func DiffExact(pointer string, expected interface{}, actual interface{}, ok bool) []Mismatch {
+	return diff(pointer, expected, actual, ok, true)
+}
+
+func diff(pointer string, expected interface{}, actual interface{}, ok bool, exact bool) []Mismatch {
+	mismatch := func(expected string) []Mismatch {
+		if pointer == "" {
+			pointer = "/"
+		}
+
+		return []Mismatch{{pointer, expected, Describe(actual, ok)}}
+	}
+
+	switch ev := expected.(type) {
+	case map[string]interface{}:
+		av, isObject := actual.(map[string]interface{})
+		if !ok || !isObject {
+			return mismatch("an object")
+		}
+
+		keys := []string{}
+		for k := range ev {
+			keys = append(keys, k)
+		}
+
+		if exact {
+			for k := range av {
+				if _, has := ev[k]; !has {
+					keys = append(keys, k)
+				}
+			}
+		}
+
+		sort.Strings(keys)
+
+		res := []Mismatch{}
+		for _, k := range keys {
+			v, has := av[k]
+			if _, expected := ev[k]; !expected {
+				res = append(res, Mismatch{Pointer(pointer, k), "nothing", Describe(v, has)})
+				continue
+			}
+
+			res = append(res, diff(Pointer(pointer, k), ev[k], v, has, exact)...)
+		}
+
+		return res
+
+	case []interface{}:
+		av, isArray := actual.([]interface{})
+		if !ok || !isArray {
+			return mismatch(fmt.Sprintf("an array of %d items", len(ev)))
+		}
+
+		if len(av) != len(ev) {
+			return []Mismatch{{pointer, fmt.Sprintf("%d items", len(ev)), fmt.Sprintf("%d items", len(av))}}
+		}
+
+		res := []Mismatch{}
+		for i := range ev {
+			res = append(res, diff(Pointer(pointer, strconv.Itoa(i)), ev[i], av[i], true, exact)...)
+		}
+
+		return res
+
+	case contract.ParameterAccess:
+		v := ev()
+		if !ok || params.Cast(actual) != v {
+			return mismatch(v)
+		}
+
+	case string:
+		if !ok || (exact && !reflect.DeepEqual(expected, actual)) || params.Cast(actual) != ev {
+			return mismatch(ev)
+		}
+
+	default:
+		if !ok || !reflect.DeepEqual(expected, actual) {
+			return mismatch(Describe(expected, true))
+		}
+	}
+
+	return []Mismatch{}
+}
@@ -0,0 +1,55 @@
+package expect_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+func Test_Diff(T *testing.T) {
+	var actual interface{}
+	json.Unmarshal([]byte(`{
+		"id": 42,
+		"name": "rex",
+		"tags": ["cute", "small"],
+		"owner": {"id": 1, "a/b": true}
+	}`), &actual)
+
+	T.Run("Match", func(T *testing.T) {
+		expected := map[string]interface{}{
+			"id":    float64(42),
+			"name":  contract.ParameterAccess(params.Value("rex")),
+			"tags":  []interface{}{"cute", "small"},
+			"owner": map[string]interface{}{"a/b": true},
+		}
+
+		assert.Empty(T, expect.Diff("", expected, actual, true))
+	})
+
+	T.Run("Mismatch", func(T *testing.T) {
+		expected := map[string]interface{}{
+			"id":    "43",
+			"nope":  nil,
+			"tags":  []interface{}{"cute"},
+			"owner": map[string]interface{}{"a/b": false, "id": []interface{}{}},
+		}
+
+		assert.Equal(T, []expect.Mismatch{
+			{Pointer: "/id", Expected: "43", Actual: "42"},
+			{Pointer: "/nope", Expected: "null", Actual: "nothing"},
+			{Pointer: "/owner/a~1b", Expected: "false", Actual: "true"},
+			{Pointer: "/owner/id", Expected: "an array of 0 items", Actual: "1"},
+			{Pointer: "/tags", Expected: "1 items", Actual: "2 items"},
+		}, expect.Diff("", expected, actual, true))
+	})
+
+	T.Run("Root", func(T *testing.T) {
+		assert.Equal(T, []expect.Mismatch{
+			{Pointer: "/", Expected: "an object", Actual: "[\"cute\",\"small\"]"},
+		}, expect.Diff("", map[string]interface{}{}, []interface{}{"cute", "small"}, true))
+	})
+}
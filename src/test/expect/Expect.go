@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
 
-	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/params"
@@ -44,12 +44,56 @@ func HeaderRequired(n string, log contract.Logger) contract.Expectation {
 	}
 }
 
+// Header is an expectation as for a response header value.
+type Header struct {
+	Name    string
+	Matcher Matcher
+}
+
+// Headers creates an expectation as for response's headers values.
+// Multiple values of a header are joined with commas.
+func Headers(headers []Header, log contract.Logger) contract.Expectation {
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return false
+		}
+
+		res := true
+
+		for _, h := range headers {
+			values := result.HTTPResponse.Header.Values(h.Name)
+
+			var actual interface{}
+			ok := len(values) > 0
+			if ok {
+				actual = strings.Join(values, ", ")
+			}
+
+			match, expected := h.Matcher(actual, ok)
+
+			log.ExpectingHeader(h.Name, expected)
+
+			if !match {
+				log.ResponseHasWrongHeaderValue(h.Name, expected, Describe(actual, ok))
+				res = false
+			}
+		}
+
+		return res
+	}
+}
+
 // HeaderSchema creates an expectation as for response's header contents
 // which must comply to the provided JSON schema.
 func HeaderSchema(n string, schema *api.Schema, log contract.Logger) contract.Expectation {
 	log.Expecting("header "+n+" to conform schema", schema.Name)
 
 	return func(result *contract.OperationResult) bool {
+		// Absent headers are HeaderRequired's business.
+		if len(result.HTTPResponse.Header.Values(n)) == 0 {
+			return true
+		}
+
 		return test.Schema(schema.Cast(result.HTTPResponse.Header.Get(n)), schema, log)
 	}
 }
@@ -100,51 +144,78 @@ func ContentSchema(schema *api.Schema, log contract.Logger) contract.Expectation
 	}
 }
 
-// JSONBody creates an expectation as for response's
-// body properties values.
-func JSONBody(props contract.Set, graph gcontract.Graph, log contract.Logger) contract.Expectation {
-	numProps := 0
-	for range props.Iterate() {
-		numProps++
+// Property is an expectation as for a response body property.
+// Properties are either top level object properties, referenced by Key,
+// or values selected with Path. Non-singular paths select arrays of values.
+type Property struct {
+	Key     string
+	Path    *params.Path
+	Matcher Matcher
+}
+
+// Value returns the property value from data, or false when there is none.
+func (prop Property) Value(data interface{}) (interface{}, bool) {
+	if prop.Path == nil {
+		obj, isObject := data.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+
+		v, ok := obj[prop.Key]
+		return v, ok
 	}
 
+	nodes := prop.Path.Select(data)
+
+	if !prop.Path.Singular() {
+		return nodes, true
+	}
+
+	if len(nodes) == 0 {
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+// JSONBody creates an expectation as for response's
+// body properties values.
+func JSONBody(props []Property, log contract.Logger) contract.Expectation {
 	return func(result *contract.OperationResult) bool {
 		if result.HTTPResponse == nil {
 			return false
 		}
 
+		if len(props) == 0 {
+			return true
+		}
+
 		respCT := strings.Split(result.HTTPResponse.Header.Get("Content-Type"), ";")[0]
 
 		switch respCT {
 		case "application/json":
-			if numProps > 0 {
-				data := make(map[string]interface{})
-				err := json.Unmarshal(result.ResponseBytes, &data)
-				if err != nil {
-					log.Error(err)
-					return false
-				}
+			var data interface{}
+			err := json.Unmarshal(result.ResponseBytes, &data)
+			if err != nil {
+				log.Error(err)
+				return false
+			}
 
-				result := true
+			result := true
 
-				for ebp := range props.Iterate() {
-					expected := ebp.V()
-					actual := params.Cast(data[ebp.N])
+			for _, prop := range props {
+				actual, ok := prop.Value(data)
+				match, expected := prop.Matcher(actual, ok)
 
-					log.ExpectingProperty(ebp.N, expected)
+				log.ExpectingProperty(prop.Key, expected)
 
-					if expected == actual {
-						///
-					} else {
-						log.ResponseHasWrongPropertyValue(ebp.N, expected, actual)
-						result = false
-					}
+				if !match {
+					log.ResponseHasWrongPropertyValue(prop.Key, expected, Describe(actual, ok))
+					result = false
 				}
-
-				return result
 			}
 
-			return true
+			return result
 
 		default:
 			log.NOMESSAGE("The Content-Type of '%s' is not supported.\n", respCT)
@@ -153,3 +224,48 @@ func JSONBody(props contract.Set, graph gcontract.Graph, log contract.Logger) co
 		return false
 	}
 }
+
+// BodyMatches creates an expectation as for response's body structure.
+// The body must contain everything the expected partial document has,
+// see Diff for details. The mismatches are logged with their JSON pointers.
+func BodyMatches(expected interface{}, log contract.Logger) contract.Expectation {
+	log.Expecting("body to match", "a document")
+
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return false
+		}
+
+		var data interface{}
+		err := json.Unmarshal(result.ResponseBytes, &data)
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+
+		mismatches := Diff("", expected, data, true)
+		for _, mm := range mismatches {
+			log.ResponseBodyMismatch(mm.Pointer, mm.Expected, mm.Actual)
+		}
+
+		return len(mismatches) == 0
+	}
+}
+
+// MaxTime creates an expectation as for the total response time.
+func MaxTime(maxTime time.Duration, log contract.Logger) contract.Expectation {
+	log.Expecting("response time under", maxTime.String())
+
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return false
+		}
+
+		if result.Timing.Total < maxTime {
+			return true
+		}
+
+		log.ResponseIsTooSlow(maxTime, result.Timing.Total)
+		return false
+	}
+}
@@ -3,11 +3,13 @@ package expect_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/test/expect"
 )
 
@@ -49,6 +51,36 @@ func Test_HeaderRequired(T *testing.T) {
 	})
 }
 
+func Test_Headers(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		HTTPResponse: &http.Response{
+			Header: http.Header{
+				"X-Rate-Limit": []string{"100"},
+				"Vary":         []string{"Accept", "Origin"},
+			},
+		},
+	}
+
+	T.Run("True", func(T *testing.T) {
+		assert.True(T, expect.Headers([]expect.Header{
+			{Name: "x-rate-limit", Matcher: expect.Greater(params.Value("10"))},
+			{Name: "Vary", Matcher: expect.Equal(params.Value("Accept, Origin"))},
+			{Name: "X-Debug", Matcher: expect.Absent()},
+		}, log)(result))
+	})
+
+	T.Run("False", func(T *testing.T) {
+		assert.False(T, expect.Headers([]expect.Header{
+			{Name: "X-Rate-Limit", Matcher: expect.Absent()},
+		}, log)(result))
+
+		assert.False(T, expect.Headers([]expect.Header{
+			{Name: "X-Debug", Matcher: expect.Exists()},
+		}, log)(result))
+	})
+}
+
 func Test_ContentType(T *testing.T) {
 	log := log.New("plain", 0)
 	result := &contract.OperationResult{
@@ -101,6 +133,16 @@ func Test_HeaderSchema(T *testing.T) {
 
 		assert.False(T, expect.HeaderSchema("X-Thing", schema, log)(result))
 	})
+
+	T.Run("Absent", func(T *testing.T) {
+		schema := &api.Schema{
+			JSONSchema: api.JSONSchema{
+				"type": "boolean",
+			},
+		}
+
+		assert.True(T, expect.HeaderSchema("X-Other-Thing", schema, log)(result))
+	})
 }
 
 func Test_ContentSchema(T *testing.T) {
@@ -140,3 +182,27 @@ func Test_ContentSchema(T *testing.T) {
 		assert.False(T, expect.ContentSchema(schema, log)(result))
 	})
 }
+
+func Test_MaxTime(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+		},
+		Timing: contract.RequestTiming{
+			Total: 250 * time.Millisecond,
+		},
+	}
+
+	T.Run("True", func(T *testing.T) {
+		assert.True(T, expect.MaxTime(300*time.Millisecond, log)(result))
+	})
+
+	T.Run("False", func(T *testing.T) {
+		assert.False(T, expect.MaxTime(200*time.Millisecond, log)(result))
+	})
+
+	T.Run("No response", func(T *testing.T) {
+		assert.False(T, expect.MaxTime(300*time.Millisecond, log)(&contract.OperationResult{}))
+	})
+}
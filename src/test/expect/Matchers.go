@@ -0,0 +1,279 @@
+package expect
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Matcher checks a value from a response. ok is false when there is no such value.
+// It returns whether the value matches and a description of what was expected,
+// like "greater than 0" or "one of [available, sold]".
+type Matcher func(actual interface{}, ok bool) (bool, string)
+
+// Describe returns a human readable representation of an actual value.
+func Describe(actual interface{}, ok bool) string {
+	if !ok {
+		return "nothing"
+	}
+
+	if actual == nil {
+		return "null"
+	}
+
+	return params.Cast(actual)
+}
+
+// Number converts a value into a number when possible.
+func Number(v interface{}) (float64, bool) {
+	switch tv := v.(type) {
+	case float64:
+		return tv, true
+
+	case string:
+		f, err := strconv.ParseFloat(tv, 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// Equal matches values equal to v.
+func Equal(v contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		expected := v()
+		return ok && params.Cast(actual) == expected, expected
+	}
+}
+
+// Exists matches present values, including nulls.
+func Exists() Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		return ok, "present"
+	}
+}
+
+// Absent matches absent values.
+func Absent() Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		return !ok, "absent"
+	}
+}
+
+// Not negates m.
+func Not(m Matcher) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		match, desc := m(actual, ok)
+		return !match, "not " + desc
+	}
+}
+
+// All matches values which match all the ms.
+func All(ms ...Matcher) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		res := true
+		descs := []string{}
+
+		for _, m := range ms {
+			match, desc := m(actual, ok)
+			res = res && match
+			descs = append(descs, desc)
+		}
+
+		return res, strings.Join(descs, " and ")
+	}
+}
+
+// Type matches values of a JSON schema type: string, number, integer, boolean, array, object or null.
+func Type(t string) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		desc := "of type " + t
+
+		if !ok {
+			return false, desc
+		}
+
+		switch tv := actual.(type) {
+		case string:
+			return t == "string", desc
+
+		case float64:
+			return t == "number" || (t == "integer" && tv == float64(int64(tv))), desc
+
+		case bool:
+			return t == "boolean", desc
+
+		case []interface{}:
+			return t == "array", desc
+
+		case map[string]interface{}:
+			return t == "object", desc
+
+		case nil:
+			return t == "null", desc
+		}
+
+		return false, desc
+	}
+}
+
+// Regex matches values matching a regular expression.
+func Regex(v contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		pattern := v()
+		desc := "matching " + pattern
+
+		rx, err := regexp.Compile(pattern)
+		if err != nil || !ok {
+			return false, desc
+		}
+
+		return rx.MatchString(params.Cast(actual)), desc
+	}
+}
+
+// Compare matches numbers which compare to v as cmp says. name describes the comparison.
+func Compare(v contract.ParameterAccess, name string, cmp func(actual float64, expected float64) bool) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		expected := v()
+		desc := name + " " + expected
+
+		ev, eok := Number(expected)
+		av, aok := Number(actual)
+
+		return ok && eok && aok && cmp(av, ev), desc
+	}
+}
+
+// Greater matches numbers greater than v.
+func Greater(v contract.ParameterAccess) Matcher {
+	return Compare(v, "greater than", func(a float64, e float64) bool { return a > e })
+}
+
+// GreaterOrEqual matches numbers greater than or equal to v.
+func GreaterOrEqual(v contract.ParameterAccess) Matcher {
+	return Compare(v, "greater than or equal to", func(a float64, e float64) bool { return a >= e })
+}
+
+// Less matches numbers less than v.
+func Less(v contract.ParameterAccess) Matcher {
+	return Compare(v, "less than", func(a float64, e float64) bool { return a < e })
+}
+
+// LessOrEqual matches numbers less than or equal to v.
+func LessOrEqual(v contract.ParameterAccess) Matcher {
+	return Compare(v, "less than or equal to", func(a float64, e float64) bool { return a <= e })
+}
+
+// Between matches numbers in the [min, max] range.
+func Between(min contract.ParameterAccess, max contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		match, _ := All(GreaterOrEqual(min), LessOrEqual(max))(actual, ok)
+		return match, "between " + min() + " and " + max()
+	}
+}
+
+// OneOf matches values equal to one of vs.
+func OneOf(vs ...contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		res := false
+		expected := []string{}
+
+		for _, v := range vs {
+			ev := v()
+			expected = append(expected, ev)
+			res = res || (ok && params.Cast(actual) == ev)
+		}
+
+		return res, "one of [" + strings.Join(expected, ", ") + "]"
+	}
+}
+
+// Contains matches strings containing v, arrays with an item equal to v
+// and objects with a property named v.
+func Contains(v contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		expected := v()
+		desc := "containing " + expected
+
+		switch tv := actual.(type) {
+		case string:
+			return strings.Contains(tv, expected), desc
+
+		case []interface{}:
+			for _, item := range tv {
+				if params.Cast(item) == expected {
+					return true, desc
+				}
+			}
+
+		case map[string]interface{}:
+			_, has := tv[expected]
+			return has, desc
+		}
+
+		return false, desc
+	}
+}
+
+// Length matches strings, arrays & objects which length matches m.
+func Length(m Matcher) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		l := -1
+
+		switch tv := actual.(type) {
+		case string:
+			l = utf8.RuneCountInString(tv)
+
+		case []interface{}:
+			l = len(tv)
+
+		case map[string]interface{}:
+			l = len(tv)
+		}
+
+		if l < 0 {
+			_, desc := m(nil, false)
+			return false, "of length " + desc
+		}
+
+		match, desc := m(float64(l), true)
+		return match, "of length " + desc
+	}
+}
+
+// StartsWith matches strings starting with v.
+func StartsWith(v contract.ParameterAccess) Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		expected := v()
+		s, isString := actual.(string)
+		return isString && strings.HasPrefix(s, expected), "starting with " + expected
+	}
+}
+
+// IsUUID matches UUID strings.
+func IsUUID() Matcher {
+	rx := regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+	return func(actual interface{}, ok bool) (bool, string) {
+		s, isString := actual.(string)
+		return isString && rx.MatchString(s), "a UUID"
+	}
+}
+
+// IsDate matches date strings, see params.ParseDate for the supported formats.
+func IsDate() Matcher {
+	return func(actual interface{}, ok bool) (bool, string) {
+		s, isString := actual.(string)
+		if !isString {
+			return false, "a date"
+		}
+
+		_, err := params.ParseDate(s)
+		return err == nil, "a date"
+	}
+}
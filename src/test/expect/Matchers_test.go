@@ -0,0 +1,107 @@
+package expect_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+func Test_Matchers(T *testing.T) {
+	var data map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"id": 42,
+		"price": 9.5,
+		"name": "rex",
+		"tags": ["cute", "small"],
+		"owner": {"id": 1},
+		"uuid": "7ff749bf-5e94-4433-a3ae-e28c3dde1246",
+		"date": "2024-01-31T00:00:00Z",
+		"null": null
+	}`), &data)
+
+	v := params.Value
+
+	match := func(key string, m expect.Matcher) bool {
+		actual, ok := data[key]
+		res, _ := m(actual, ok)
+		return res
+	}
+
+	T.Run("Equal", func(T *testing.T) {
+		assert.True(T, match("id", expect.Equal(v("42"))))
+		assert.False(T, match("id", expect.Equal(v("43"))))
+		assert.False(T, match("nope", expect.Equal(v(""))))
+	})
+
+	T.Run("Exists & Absent", func(T *testing.T) {
+		assert.True(T, match("null", expect.Exists()))
+		assert.False(T, match("nope", expect.Exists()))
+		assert.True(T, match("nope", expect.Absent()))
+		assert.True(T, match("id", expect.Not(expect.Absent())))
+	})
+
+	T.Run("Type", func(T *testing.T) {
+		assert.True(T, match("id", expect.Type("integer")))
+		assert.True(T, match("id", expect.Type("number")))
+		assert.False(T, match("price", expect.Type("integer")))
+		assert.True(T, match("name", expect.Type("string")))
+		assert.True(T, match("tags", expect.Type("array")))
+		assert.True(T, match("owner", expect.Type("object")))
+		assert.True(T, match("null", expect.Type("null")))
+		assert.False(T, match("nope", expect.Type("null")))
+	})
+
+	T.Run("Regex", func(T *testing.T) {
+		assert.True(T, match("name", expect.Regex(v("^r.x$"))))
+		assert.False(T, match("name", expect.Regex(v("^x"))))
+	})
+
+	T.Run("Comparisons", func(T *testing.T) {
+		assert.True(T, match("id", expect.Greater(v("41"))))
+		assert.False(T, match("id", expect.Greater(v("42"))))
+		assert.True(T, match("id", expect.GreaterOrEqual(v("42"))))
+		assert.True(T, match("price", expect.Less(v("10"))))
+		assert.True(T, match("price", expect.LessOrEqual(v("9.5"))))
+		assert.False(T, match("name", expect.Less(v("10"))))
+		assert.True(T, match("price", expect.Between(v("1"), v("10"))))
+		assert.False(T, match("id", expect.Between(v("1"), v("10"))))
+	})
+
+	T.Run("OneOf", func(T *testing.T) {
+		assert.True(T, match("name", expect.OneOf(v("tom"), v("rex"))))
+		assert.False(T, match("name", expect.OneOf(v("tom"))))
+	})
+
+	T.Run("Contains", func(T *testing.T) {
+		assert.True(T, match("name", expect.Contains(v("ex"))))
+		assert.True(T, match("tags", expect.Contains(v("cute"))))
+		assert.False(T, match("tags", expect.Contains(v("big"))))
+		assert.True(T, match("owner", expect.Contains(v("id"))))
+	})
+
+	T.Run("Length", func(T *testing.T) {
+		assert.True(T, match("tags", expect.Length(expect.Equal(v("2")))))
+		assert.True(T, match("name", expect.Length(expect.Greater(v("0")))))
+		assert.False(T, match("id", expect.Length(expect.Greater(v("0")))))
+	})
+
+	T.Run("StartsWith", func(T *testing.T) {
+		assert.True(T, match("name", expect.StartsWith(v("re"))))
+		assert.False(T, match("id", expect.StartsWith(v("4"))))
+	})
+
+	T.Run("IsUUID & IsDate", func(T *testing.T) {
+		assert.True(T, match("uuid", expect.IsUUID()))
+		assert.False(T, match("name", expect.IsUUID()))
+		assert.True(T, match("date", expect.IsDate()))
+		assert.False(T, match("name", expect.IsDate()))
+	})
+
+	T.Run("Description", func(T *testing.T) {
+		_, desc := expect.All(expect.Greater(v("0")), expect.Length(expect.Less(v("5"))))(nil, false)
+		assert.Equal(T, "greater than 0 and of length less than 5", desc)
+	})
+}
@@ -0,0 +1,163 @@
+package expect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// UpdateSnapshots makes the snapshot expectations rewrite
+// the snapshot files instead of comparing responses with them.
+var UpdateSnapshots = false
+
+// Ignored is what the ignored values are replaced with in snapshots.
+const Ignored = "<ignored>"
+
+// Snapshot is a normalized response stored in a snapshot file.
+// JSON bodies are stored as JSON documents, the other ones as strings.
+type Snapshot struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body"`
+}
+
+// SnapshotOptions describe what to take to a snapshot and where to store it.
+// The Content-Type header is always taken, the Headers are in addition to it.
+// The values selected by Ignore paths in bodies are replaced with the Ignored string.
+type SnapshotOptions struct {
+	Path    string
+	Headers []string
+	Ignore  []*params.Path
+}
+
+// NewSnapshot creates a snapshot of the response.
+func NewSnapshot(resp *http.Response, body []byte, opts SnapshotOptions) *Snapshot {
+	snap := &Snapshot{
+		Status:  resp.StatusCode,
+		Headers: map[string]string{},
+	}
+
+	for _, name := range append([]string{"Content-Type"}, opts.Headers...) {
+		if v := resp.Header.Get(name); v != "" {
+			snap.Headers[http.CanonicalHeaderKey(name)] = v
+		}
+	}
+
+	if err := json.Unmarshal(body, &snap.Body); err != nil {
+		snap.Body = string(body)
+	}
+
+	snap.Ignore(opts.Ignore)
+
+	return snap
+}
+
+// LoadSnapshot reads a snapshot from the file at path.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+	err = json.Unmarshal(data, snap)
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the snapshot file '"+path+"'.", err)
+	}
+
+	return snap, nil
+}
+
+// Save writes the snapshot to the file at path, creating the directories if needed.
+func (snap *Snapshot) Save(path string) error {
+	data := &bytes.Buffer{}
+	enc := json.NewEncoder(data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(snap)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data.Bytes(), 0644)
+}
+
+// Ignore replaces the values selected by paths with the Ignored string.
+func (snap *Snapshot) Ignore(paths []*params.Path) {
+	for _, path := range paths {
+		for _, node := range path.Locate(snap.Body) {
+			snap.Body = params.Replace(snap.Body, node.Location, Ignored)
+		}
+	}
+}
+
+// Document converts the snapshot to a JSON document, so it can be compared.
+func (snap *Snapshot) Document() interface{} {
+	headers := map[string]interface{}{}
+	for name, v := range snap.Headers {
+		headers[name] = v
+	}
+
+	return map[string]interface{}{
+		"status":  float64(snap.Status),
+		"headers": headers,
+		"body":    snap.Body,
+	}
+}
+
+// MatchesSnapshot creates an expectation as for the response
+// to be the same as the one recorded in the snapshot file.
+// When there is no snapshot file yet, or when UpdateSnapshots is set,
+// the response is recorded instead.
+func MatchesSnapshot(opts SnapshotOptions, log contract.Logger) contract.Expectation {
+	log.Expecting("response to match the snapshot", opts.Path)
+
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return false
+		}
+
+		actual := NewSnapshot(result.HTTPResponse, result.ResponseBytes, opts)
+
+		expected, err := LoadSnapshot(opts.Path)
+
+		if UpdateSnapshots || os.IsNotExist(err) {
+			err = actual.Save(opts.Path)
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+
+			log.SnapshotSaved(opts.Path)
+			return true
+		}
+
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+
+		// The ignore list might have changed since the snapshot was taken.
+		expected.Ignore(opts.Ignore)
+
+		mismatches := DiffExact("", expected.Document(), actual.Document(), true)
+		for _, mm := range mismatches {
+			log.ResponseSnapshotMismatch(mm.Pointer, mm.Expected, mm.Actual)
+		}
+
+		return len(mismatches) == 0
+	}
+}
@@ -0,0 +1,80 @@
+package expect_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+func Test_MatchesSnapshot(T *testing.T) {
+	log := log.New("plain", 0)
+
+	dir, _ := ioutil.TempDir("", "oasis")
+	defer os.RemoveAll(dir)
+
+	result := func(body string, total string) *contract.OperationResult {
+		return &contract.OperationResult{
+			HTTPResponse: &http.Response{
+				StatusCode: 200,
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+					"X-Total":      []string{total},
+					"Date":         []string{"today"},
+				},
+			},
+			ResponseBytes: []byte(body),
+		}
+	}
+
+	ignore, _ := params.ParsePath("[*].createdAt")
+
+	opts := expect.SnapshotOptions{
+		Path:    filepath.Join(dir, "snapshots", "list.json"),
+		Headers: []string{"x-total"},
+		Ignore:  []*params.Path{ignore},
+	}
+
+	T.Run("Record", func(T *testing.T) {
+		assert.True(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 1, "createdAt": "now"}]`, "1")))
+
+		snap, err := expect.LoadSnapshot(opts.Path)
+		assert.Nil(T, err)
+		assert.Equal(T, &expect.Snapshot{
+			Status: 200,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"X-Total":      "1",
+			},
+			Body: []interface{}{
+				map[string]interface{}{"id": float64(1), "createdAt": expect.Ignored},
+			},
+		}, snap)
+	})
+
+	T.Run("Match", func(T *testing.T) {
+		assert.True(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 1, "createdAt": "later"}]`, "1")))
+	})
+
+	T.Run("Mismatch", func(T *testing.T) {
+		assert.False(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 2, "createdAt": "now"}]`, "1")))
+		assert.False(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 1, "createdAt": "now"}]`, "2")))
+		assert.False(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 1, "createdAt": "now", "name": "rex"}]`, "1")))
+	})
+
+	T.Run("Update", func(T *testing.T) {
+		expect.UpdateSnapshots = true
+		defer func() { expect.UpdateSnapshots = false }()
+
+		assert.True(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 2}]`, "1")))
+		expect.UpdateSnapshots = false
+		assert.True(T, expect.MatchesSnapshot(opts, log)(result(`[{"id": 2}]`, "1")))
+	})
+}
@@ -0,0 +1,136 @@
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// HAR is an HTTP Archive, a JSON log of HTTP exchanges
+// exported by browsers & proxies.
+// Only the parts needed to check the exchanges are read.
+type HAR struct {
+	Log struct {
+		Entries []Entry `json:"entries"`
+	} `json:"log"`
+}
+
+// Entry is a recorded HTTP exchange.
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+}
+
+// Header is a recorded HTTP header.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method   string   `json:"method"`
+	URL      string   `json:"url"`
+	Headers  []Header `json:"headers"`
+	PostData *struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	} `json:"postData"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status  int      `json:"status"`
+	Headers []Header `json:"headers"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+// Load reads a HAR file.
+func Load(path string) (*HAR, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	har := &HAR{}
+	err = json.Unmarshal(data, har)
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the HAR file '"+path+"'.", err)
+	}
+
+	return har, nil
+}
+
+// MakeHeader creates an http.Header from the recorded headers.
+// HTTP/2 pseudo headers, like ":authority", are skipped.
+func MakeHeader(headers []Header) http.Header {
+	res := http.Header{}
+
+	for _, h := range headers {
+		if !strings.HasPrefix(h.Name, ":") {
+			res.Add(h.Name, h.Value)
+		}
+	}
+
+	return res
+}
+
+// HTTPRequest recreates the recorded request & it's body.
+func (entry Entry) HTTPRequest() (*http.Request, []byte, error) {
+	body := []byte{}
+	if entry.Request.PostData != nil {
+		body = []byte(entry.Request.PostData.Text)
+	}
+
+	req, err := http.NewRequest(entry.Request.Method, entry.Request.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header = MakeHeader(entry.Request.Headers)
+
+	if req.Header.Get("Content-Type") == "" && entry.Request.PostData != nil && entry.Request.PostData.MimeType != "" {
+		req.Header.Set("Content-Type", entry.Request.PostData.MimeType)
+	}
+
+	return req, body, nil
+}
+
+// HTTPResponse recreates the recorded response & it's body.
+// The content is stored decompressed in HAR files, sometimes base64-encoded.
+func (entry Entry) HTTPResponse(req *http.Request) (*http.Response, []byte, error) {
+	body := []byte(entry.Response.Content.Text)
+
+	if entry.Response.Content.Encoding == "base64" {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(entry.Response.Content.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	header := MakeHeader(entry.Response.Headers)
+	header.Del("Content-Encoding")
+
+	// Browsers put "x-unknown" there for the responses without a content type.
+	if header.Get("Content-Type") == "" && entry.Response.Content.MimeType != "" && entry.Response.Content.MimeType != "x-unknown" {
+		header.Set("Content-Type", entry.Response.Content.MimeType)
+	}
+
+	return &http.Response{
+		StatusCode: entry.Response.Status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, body, nil
+}
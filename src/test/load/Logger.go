@@ -0,0 +1,52 @@
+package load
+
+import (
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Logger is used in place of the regular logger during load tests.
+// It passes everything through to a (supposedly quiet) inner logger
+// and remembers whether a schema validation has failed.
+// Every operation gets it's own Logger instance via Clone(),
+// so the flag tells about that operation only.
+type Logger struct {
+	contract.Logger
+	schemaFailed bool
+}
+
+// NewLogger creates a new Logger instance.
+func NewLogger(inner contract.Logger) *Logger {
+	return &Logger{
+		Logger: inner,
+	}
+}
+
+// Clone creates a new Logger instance sharing the same inner logger.
+func (log *Logger) Clone() contract.Logger {
+	return NewLogger(log.Logger)
+}
+
+// Buffer does nothing as there is no per-request output in load tests.
+func (log *Logger) Buffer(enabled bool) {
+}
+
+// Flush does nothing as there is no per-request output in load tests.
+func (log *Logger) Flush() {
+}
+
+// SchemaFail remembers the schema failure.
+func (log *Logger) SchemaFail(schemaName string, errors []gojsonschema.ResultError) {
+	log.schemaFailed = true
+	log.Logger.SchemaFail(schemaName, errors)
+}
+
+// SchemaFailed tells whether log has witnessed a schema failure.
+// It is false for loggers other than load.Logger.
+func SchemaFailed(log contract.Logger) bool {
+	if llog, ok := log.(*Logger); ok {
+		return llog.schemaFailed
+	}
+
+	return false
+}
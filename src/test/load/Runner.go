@@ -0,0 +1,109 @@
+package load
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Job is a single unit of work performed repeatedly during a load test.
+// It is supposed to record the outcome of it's operations in stats.
+type Job func(stats *Stats)
+
+// Clock produces the ticks which start jobs and the deadline of a load test.
+type Clock interface {
+	Ticker(d time.Duration) (<-chan time.Time, func())
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the Clock of the time package.
+type RealClock struct{}
+
+// Ticker creates a time.Ticker and returns it's channel & stop function.
+func (RealClock) Ticker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+// After calls time.After.
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Runner executes a Job repeatedly at a fixed rate using a pool of workers.
+type Runner struct {
+	RPS      int64
+	Duration time.Duration
+	Workers  int64
+
+	// Clock is RealClock when not set.
+	Clock Clock
+}
+
+// Validate checks the runner settings.
+func (runner Runner) Validate() error {
+	if runner.RPS <= 0 {
+		return errors.Oops(fmt.Sprintf("The request rate must be positive, %d rps given.", runner.RPS), nil)
+	}
+
+	if runner.Workers <= 0 {
+		return errors.Oops(fmt.Sprintf("The number of workers must be positive, %d given.", runner.Workers), nil)
+	}
+
+	if runner.Duration <= 0 {
+		return errors.Oops(fmt.Sprintf("The load test duration must be positive, %s given.", runner.Duration), nil)
+	}
+
+	return nil
+}
+
+// Run starts the load test and blocks until it is complete.
+// The runner settings are supposed to be valid, see Validate.
+func (runner Runner) Run(job Job) *Stats {
+	clock := runner.Clock
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	stats := NewStats()
+	jobs := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	for w := int64(0); w < runner.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			for range jobs {
+				job(stats)
+			}
+			wg.Done()
+		}()
+	}
+
+	ticks, stop := clock.Ticker(time.Second / time.Duration(runner.RPS))
+	deadline := clock.After(runner.Duration)
+
+loop:
+	for {
+		select {
+		case <-deadline:
+			break loop
+
+		case <-ticks:
+			select {
+			case jobs <- struct{}{}:
+			case <-deadline:
+				break loop
+			}
+		}
+	}
+
+	stop()
+	close(jobs)
+	wg.Wait()
+
+	stats.End = time.Now()
+
+	return stats
+}
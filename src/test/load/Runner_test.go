@@ -0,0 +1,97 @@
+package load_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/load"
+)
+
+// clock is a Clock which ticks when told to.
+type clock struct {
+	ticks    chan time.Time
+	deadline chan time.Time
+	interval time.Duration
+	duration time.Duration
+}
+
+func (c *clock) Ticker(d time.Duration) (<-chan time.Time, func()) {
+	c.interval = d
+	return c.ticks, func() {}
+}
+
+func (c *clock) After(d time.Duration) <-chan time.Time {
+	c.duration = d
+	return c.deadline
+}
+
+func Test_Runner(T *testing.T) {
+	c := &clock{
+		ticks:    make(chan time.Time),
+		deadline: make(chan time.Time),
+	}
+
+	runner := load.Runner{
+		RPS:      100,
+		Duration: 200 * time.Millisecond,
+		Workers:  4,
+		Clock:    c,
+	}
+
+	runs := int64(0)
+	done := make(chan struct{})
+
+	go func() {
+		// Every tick starts a job, the deadline stops the run.
+		for i := 0; i < 20; i++ {
+			c.ticks <- time.Now()
+			<-done
+		}
+
+		close(c.deadline)
+	}()
+
+	stats := runner.Run(func(stats *load.Stats) {
+		atomic.AddInt64(&runs, 1)
+		stats.Add(result(200, true, time.Millisecond), false)
+		done <- struct{}{}
+	})
+
+	report := stats.Report()
+
+	assert.Equal(T, int64(20), runs)
+	assert.Equal(T, 20, report.Requests)
+	assert.Equal(T, 10*time.Millisecond, c.interval)
+	assert.Equal(T, runner.Duration, c.duration)
+}
+
+func Test_RunnerValidate(T *testing.T) {
+	valid := load.Runner{RPS: 10, Duration: time.Second, Workers: 1}
+	assert.Nil(T, valid.Validate())
+
+	for _, runner := range []load.Runner{
+		{RPS: 0, Duration: time.Second, Workers: 1},
+		{RPS: -5, Duration: time.Second, Workers: 1},
+		{RPS: 10, Duration: time.Second, Workers: 0},
+		{RPS: 10, Duration: time.Second, Workers: -1},
+		{RPS: 10, Duration: 0, Workers: 1},
+	} {
+		assert.NotNil(T, runner.Validate(), "%#v", runner)
+	}
+}
+
+func Test_Logger(T *testing.T) {
+	logger := load.NewLogger(log.NewPlain(0))
+	oplog1 := logger.Clone()
+	oplog2 := logger.Clone()
+
+	oplog1.SchemaFail("Response", nil)
+
+	assert.True(T, load.SchemaFailed(oplog1))
+	assert.False(T, load.SchemaFailed(oplog2))
+	assert.False(T, load.SchemaFailed(logger))
+	assert.False(T, load.SchemaFailed(log.NewPlain(0)))
+}
@@ -0,0 +1,135 @@
+package load
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Stats accumulates the outcomes of operations executed during a load test.
+// It is safe to use from multiple workers at once.
+type Stats struct {
+	Start time.Time
+	End   time.Time
+
+	mutex          sync.Mutex
+	latencies      []time.Duration
+	statuses       map[int]int
+	failures       map[int]int
+	schemaFailures int
+}
+
+// NewStats creates a new Stats instance.
+func NewStats() *Stats {
+	return &Stats{
+		Start:     time.Now(),
+		latencies: []time.Duration{},
+		statuses:  make(map[int]int),
+		failures:  make(map[int]int),
+	}
+}
+
+// Add records an operation result.
+func (stats *Stats) Add(result *contract.OperationResult, schemaFailed bool) {
+	if result == nil {
+		return
+	}
+
+	status := 0
+	if result.HTTPResponse != nil {
+		status = result.HTTPResponse.StatusCode
+	}
+
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	stats.latencies = append(stats.latencies, result.Timing.Total)
+	stats.statuses[status]++
+
+	if !result.Success {
+		stats.failures[status]++
+	}
+
+	if schemaFailed {
+		stats.schemaFailures++
+	}
+}
+
+// Percentile returns the p-th (0-100) percentile of the recorded latencies
+// using the nearest-rank method.
+func (stats *Stats) Percentile(p float64) time.Duration {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	return percentile(stats.sortedLatencies(), p)
+}
+
+// Report creates a summary of the collected stats.
+func (stats *Stats) Report() contract.LoadReport {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	end := stats.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	report := contract.LoadReport{
+		Requests:       len(stats.latencies),
+		Duration:       end.Sub(stats.Start),
+		Statuses:       make(map[int]int),
+		Failures:       make(map[int]int),
+		SchemaFailures: stats.schemaFailures,
+	}
+
+	for s, n := range stats.statuses {
+		report.Statuses[s] = n
+	}
+
+	for s, n := range stats.failures {
+		report.Failures[s] = n
+	}
+
+	if report.Duration > 0 {
+		report.Throughput = float64(report.Requests) / report.Duration.Seconds()
+	}
+
+	sorted := stats.sortedLatencies()
+	report.P50 = percentile(sorted, 50)
+	report.P90 = percentile(sorted, 90)
+	report.P99 = percentile(sorted, 99)
+
+	return report
+}
+
+// sortedLatencies returns a sorted copy of the recorded latencies.
+func (stats *Stats) sortedLatencies() []time.Duration {
+	sorted := make([]time.Duration, len(stats.latencies))
+	copy(sorted, stats.latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted
+}
+
+// percentile picks the p-th percentile from an already sorted slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return sorted[rank]
+}
@@ -0,0 +1,58 @@
+package load_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/test/load"
+)
+
+func result(status int, success bool, total time.Duration) *contract.OperationResult {
+	res := &contract.OperationResult{
+		Success: success,
+		Timing: contract.RequestTiming{
+			Total: total,
+		},
+	}
+
+	if status != 0 {
+		res.HTTPResponse = &http.Response{
+			StatusCode: status,
+		}
+	}
+
+	return res
+}
+
+func Test_Stats(T *testing.T) {
+	stats := load.NewStats()
+
+	for i := 1; i <= 100; i++ {
+		stats.Add(result(200, true, time.Duration(i)*time.Millisecond), false)
+	}
+
+	stats.Add(result(500, false, time.Second), true)
+	stats.Add(result(0, false, 0), false)
+	stats.Add(nil, false)
+
+	report := stats.Report()
+
+	assert.Equal(T, 102, report.Requests)
+	assert.Equal(T, map[int]int{200: 100, 500: 1, 0: 1}, report.Statuses)
+	assert.Equal(T, map[int]int{500: 1, 0: 1}, report.Failures)
+	assert.Equal(T, 1, report.SchemaFailures)
+	assert.Equal(T, 50*time.Millisecond, report.P50)
+	assert.Equal(T, 91*time.Millisecond, report.P90)
+	assert.Equal(T, 100*time.Millisecond, report.P99)
+	assert.Equal(T, time.Second, stats.Percentile(100))
+}
+
+func Test_Stats_Empty(T *testing.T) {
+	report := load.NewStats().Report()
+
+	assert.Equal(T, 0, report.Requests)
+	assert.Equal(T, time.Duration(0), report.P99)
+}
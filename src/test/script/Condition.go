@@ -0,0 +1,136 @@
+package script
+
+import (
+	"strconv"
+	gostrings "strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Condition is a boolean expression which compares two values,
+// literal or referenced from operation results,
+// like "#self.response.body.status == done".
+type Condition struct {
+	Expression string
+	Left       contract.ParameterAccess
+	Operator   string
+	Right      contract.ParameterAccess
+
+	// Refs are the references in the operands. Conditions are false
+	// when some of the referenced values are missing.
+	Refs []params.Reference
+}
+
+// conditionOperators are the comparison operators, the longer ones go first.
+var conditionOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// ParseCondition splits a condition expression into left & right operands
+// and the comparison operator between them. Operators inside brackets & parentheses,
+// like in "#op.response.items[?(@.status=='done')].id != none", are a part of the operands.
+func ParseCondition(expr string) (string, string, string, error) {
+	depth := 0
+	quote := byte(0)
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+
+		case depth > 0 && (c == '\'' || c == '"'):
+			quote = c
+
+		case c == '[' || c == '(':
+			depth++
+
+		case c == ']' || c == ')':
+			depth--
+
+		case depth == 0:
+			for _, op := range conditionOperators {
+				if !gostrings.HasPrefix(expr[i:], op) {
+					continue
+				}
+
+				left := gostrings.TrimSpace(expr[:i])
+				right := gostrings.TrimSpace(expr[i+len(op):])
+
+				if left != "" && right != "" {
+					return Unquote(left), op, Unquote(right), nil
+				}
+			}
+		}
+	}
+
+	return "", "", "", errors.Oops("Cannot parse the condition '"+expr+"'. It should look like 'LEFT == RIGHT', where '==' is one of ==, !=, <, <=, > or >=.", nil)
+}
+
+// Unquote removes the surrounding quotes from literal values.
+func Unquote(v string) string {
+	if len(v) >= 2 {
+		if (v[0] == '"' && v[len(v)-1] == '"') || (v[0] == '\'' && v[len(v)-1] == '\'') {
+			return v[1 : len(v)-1]
+		}
+	}
+
+	return v
+}
+
+// Eval computes the operand values and compares them.
+// Values are compared as numbers when both of them are numeric,
+// otherwise as strings. Missing values, like fields absent from responses
+// or responses which are not received yet, make conditions false.
+func (cond *Condition) Eval() bool {
+	for _, ref := range cond.Refs {
+		if _, ok := ref.Lookup(); !ok {
+			return false
+		}
+	}
+
+	left := cond.Left()
+	right := cond.Right()
+
+	lf, lerr := strconv.ParseFloat(left, 64)
+	rf, rerr := strconv.ParseFloat(right, 64)
+
+	if lerr == nil && rerr == nil {
+		switch cond.Operator {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+
+	switch cond.Operator {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case "<":
+		return gostrings.Compare(left, right) < 0
+	case "<=":
+		return gostrings.Compare(left, right) <= 0
+	case ">":
+		return gostrings.Compare(left, right) > 0
+	case ">=":
+		return gostrings.Compare(left, right) >= 0
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package script_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_ParseCondition(T *testing.T) {
+	T.Run("Reference", func(T *testing.T) {
+		left, op, right, err := script.ParseCondition("#self.response.status == done")
+		assert.Nil(T, err)
+		assert.Equal(T, "#self.response.status", left)
+		assert.Equal(T, "==", op)
+		assert.Equal(T, "done", right)
+	})
+
+	T.Run("Quoted", func(T *testing.T) {
+		left, op, right, err := script.ParseCondition(`#op.response.count>='10'`)
+		assert.Nil(T, err)
+		assert.Equal(T, "#op.response.count", left)
+		assert.Equal(T, ">=", op)
+		assert.Equal(T, "10", right)
+	})
+
+	T.Run("Filter", func(T *testing.T) {
+		left, op, right, err := script.ParseCondition(`#op.items[?(@.status=='done')].id != ""`)
+		assert.Nil(T, err)
+		assert.Equal(T, "#op.items[?(@.status=='done')].id", left)
+		assert.Equal(T, "!=", op)
+		assert.Equal(T, "", right)
+
+		left, op, right, err = script.ParseCondition(`#op.response[?(@.name=='a ] >= b')].age>=2`)
+		assert.Nil(T, err)
+		assert.Equal(T, "#op.response[?(@.name=='a ] >= b')].age", left)
+		assert.Equal(T, ">=", op)
+		assert.Equal(T, "2", right)
+	})
+
+	T.Run("Error", func(T *testing.T) {
+		_, _, _, err := script.ParseCondition("#self.response.status")
+		assert.NotNil(T, err)
+	})
+}
+
+func Test_ConditionEval(T *testing.T) {
+	cond := func(left string, op string, right string) *script.Condition {
+		return &script.Condition{
+			Left:     params.Value(left),
+			Operator: op,
+			Right:    params.Value(right),
+		}
+	}
+
+	T.Run("Strings", func(T *testing.T) {
+		assert.True(T, cond("done", "==", "done").Eval())
+		assert.False(T, cond("pending", "==", "done").Eval())
+		assert.True(T, cond("pending", "!=", "done").Eval())
+	})
+
+	T.Run("Numbers", func(T *testing.T) {
+		assert.True(T, cond("10", ">", "9").Eval())
+		assert.True(T, cond("202", "==", "202.0").Eval())
+		assert.False(T, cond("2", ">=", "10").Eval())
+	})
+}
+
+func Test_ConditionEvalMissing(T *testing.T) {
+	cond := func(result *contract.OperationResult, selector string, right string) *script.Condition {
+		ref := params.Reference{
+			OpID:     "listPets",
+			Result:   result,
+			Part:     params.PartResponseBody,
+			Selector: selector,
+			Log:      log.NewPlain(0),
+		}
+
+		return &script.Condition{
+			Left: func() string {
+				v, _ := ref.Lookup()
+				return v
+			},
+			Operator: "==",
+			Right:    params.Value(right),
+			Refs:     []params.Reference{ref},
+		}
+	}
+
+	response := func(status int, body string) *contract.OperationResult {
+		return &contract.OperationResult{
+			HTTPResponse:  &http.Response{StatusCode: status},
+			ResponseBytes: []byte(body),
+		}
+	}
+
+	T.Run("Present", func(T *testing.T) {
+		assert.True(T, cond(response(200, `[{"mfaRequired": true}]`), "[0].mfaRequired", "true").Eval())
+	})
+
+	T.Run("Missing field", func(T *testing.T) {
+		assert.False(T, cond(response(200, `[{"name": "Rex"}]`), "[0].mfaRequired", "true").Eval())
+		assert.False(T, cond(response(200, `[]`), "[0].mfaRequired", "").Eval())
+	})
+
+	T.Run("Non-JSON body", func(T *testing.T) {
+		assert.False(T, cond(response(202, ``), ".status", "done").Eval())
+		assert.False(T, cond(response(200, `<pets/>`), ".status", "done").Eval())
+	})
+
+	T.Run("No response", func(T *testing.T) {
+		assert.False(T, cond(&contract.OperationResult{}, ".status", "").Eval())
+	})
+}
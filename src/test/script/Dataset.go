@@ -0,0 +1,265 @@
+package script
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	gostrings "strings"
+
+	"github.com/go-yaml/yaml"
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Dataset corresponds to the 'dataset' block of a script file.
+// It can be either a path to a CSV or JSON file, a list of inline rows,
+// or a map with those and a list of operations to execute per row.
+type Dataset struct {
+	File       string   `yaml:"file"`
+	Rows       []VarMap `yaml:"rows"`
+	Operations []string `yaml:"operations"`
+}
+
+// UnmarshalYAML allows the 'dataset' block to be a file path or a list of rows.
+func (ds *Dataset) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&ds.File); err == nil {
+		return nil
+	}
+
+	if err := unmarshal(&ds.Rows); err == nil {
+		return nil
+	}
+
+	type plain Dataset
+	return unmarshal((*plain)(ds))
+}
+
+// PerRow tells whether the operation opRefID should be executed once per row.
+// When the dataset lists no operations, all of them are.
+func (ds *Dataset) PerRow(opRefID string) bool {
+	if len(ds.Operations) == 0 {
+		return true
+	}
+
+	for _, op := range ds.Operations {
+		if op == opRefID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadRows reads the rows of the dataset file, relative to the script file at scriptPath,
+// followed by the inline rows.
+func (ds *Dataset) LoadRows(scriptPath string) ([]VarMap, error) {
+	rows := []VarMap{}
+
+	if ds.File != "" {
+		path := RelativePath(scriptPath, ds.File)
+
+		var fileRows []VarMap
+		var err error
+
+		switch gostrings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			fileRows, err = LoadCSVRows(path)
+		case ".json":
+			fileRows, err = LoadJSONRows(path)
+		default:
+			err = errors.Oops("The dataset file "+path+" should be either CSV or JSON.", nil)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, fileRows...)
+	}
+
+	rows = append(rows, ds.Rows...)
+
+	if len(rows) == 0 {
+		return nil, errors.Oops("The dataset contains no rows.", nil)
+	}
+
+	return rows, nil
+}
+
+// LoadCSVRows reads rows from a CSV file. It's first line contains column names.
+func LoadCSVRows(path string) ([]VarMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the dataset file "+path+".", err)
+	}
+
+	rows := []VarMap{}
+
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	for _, record := range records[1:] {
+		row := VarMap{}
+		for ci, column := range records[0] {
+			row[column] = record[ci]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// LoadJSONRows reads rows from a JSON file which contains an array of objects.
+func LoadJSONRows(path string) ([]VarMap, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{}
+	err = json.Unmarshal(fileData, &records)
+	if err != nil {
+		return nil, errors.Oops("Cannot parse the dataset file "+path+". It should contain an array of objects.", err)
+	}
+
+	rows := []VarMap{}
+	for _, record := range records {
+		row := VarMap{}
+		for column, v := range record {
+			row[column] = params.Cast(v)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// LoadDataset reads the dataset of a script file and it's rows.
+// It returns nils when the script has no dataset.
+func LoadDataset(path string) (*Dataset, []VarMap, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	script := struct {
+		Dataset *Dataset `yaml:"dataset"`
+	}{}
+
+	err = yaml.Unmarshal(fileData, &script)
+	if err != nil {
+		return nil, nil, errors.Oops("Cannot parse the script file "+path+".", err)
+	}
+
+	if script.Dataset == nil {
+		return nil, nil, nil
+	}
+
+	rows, err := script.Dataset.LoadRows(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return script.Dataset, rows, nil
+}
+
+// RowVars adds the row values to vars as "row.column" variables.
+func RowVars(vars VarMap, row VarMap) VarMap {
+	res := VarMap{}
+
+	for vn, vv := range vars {
+		res[vn] = vv
+	}
+
+	for column, v := range row {
+		res["row."+column] = v
+	}
+
+	return res
+}
+
+// DatasetExecutor executes a script once per each dataset row.
+// Every row has it's own execution graph & results. Operations
+// not listed in the dataset are executed only once, in the first row,
+// and their results are shared with the rest of rows.
+// OnExecuted, when set, is called after every row with it's script & graph.
+type DatasetExecutor struct {
+	contract.EntityTrait
+
+	Dataset    *Dataset
+	Rows       []VarMap
+	OnExecuted func(contract.Script, gcontract.Graph)
+}
+
+// NewDatasetExecutor creates a new DatasetExecutor instance.
+func NewDatasetExecutor(logger contract.Logger, dataset *Dataset, rows []VarMap) *DatasetExecutor {
+	return &DatasetExecutor{
+		EntityTrait: contract.Entity(logger),
+		Dataset:     dataset,
+		Rows:        rows,
+	}
+}
+
+// Execute executes the script file at path for every row.
+// It returns true when all the rows have succeeded.
+func (ex DatasetExecutor) Execute(path string, vars VarMap) bool {
+	var firstGraph gcontract.Graph
+	results := []bool{}
+
+	for ri, row := range ex.Rows {
+		ex.Log.DatasetRow(int64(ri), int64(len(ex.Rows)), row)
+
+		s := Load(path, RowVars(vars, row), ex.Log)
+		graph := s.GetExecutionGraph()
+
+		if firstGraph == nil {
+			firstGraph = graph
+		} else {
+			ex.Share(firstGraph, graph)
+		}
+
+		results = append(results, NewExecutor(ex.Log, s).Execute(graph))
+
+		if ex.OnExecuted != nil {
+			ex.OnExecuted(s, graph)
+		}
+	}
+
+	ex.Log.DatasetReport(results)
+
+	for _, success := range results {
+		if !success {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Share copies the results of the operations which are not executed per row
+// from the graph of the first row.
+func (ex DatasetExecutor) Share(firstGraph gcontract.Graph, graph gcontract.Graph) {
+	for _n := range graph.Nodes().Range() {
+		n := _n.(*ExecutionNode)
+
+		if !ex.Dataset.PerRow(n.OpRefID) {
+			if _fn := firstGraph.Node(n.ID()); _fn != nil {
+				n.Share(_fn.(*ExecutionNode))
+			}
+		}
+	}
+}
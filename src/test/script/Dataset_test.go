@@ -0,0 +1,91 @@
+package script_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_Dataset(T *testing.T) {
+	dir, _ := ioutil.TempDir("", "oasis")
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		ioutil.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	write("users.csv", "merchant,consumer\nm1,c1\nm2,c2\n")
+	write("users.json", `[{"merchant": "m3", "amount": 42}]`)
+
+	T.Run("CSV", func(T *testing.T) {
+		path := write("csv.yaml", "dataset: users.csv\n")
+
+		ds, rows, err := script.LoadDataset(path)
+		assert.Nil(T, err)
+		assert.True(T, ds.PerRow("login"))
+		assert.Equal(T, []script.VarMap{
+			{"merchant": "m1", "consumer": "c1"},
+			{"merchant": "m2", "consumer": "c2"},
+		}, rows)
+	})
+
+	T.Run("JSON", func(T *testing.T) {
+		path := write("json.yaml", "dataset:\n  file: users.json\n  operations: [createPayment]\n")
+
+		ds, rows, err := script.LoadDataset(path)
+		assert.Nil(T, err)
+		assert.True(T, ds.PerRow("createPayment"))
+		assert.False(T, ds.PerRow("login"))
+		assert.Equal(T, []script.VarMap{{"merchant": "m3", "amount": "42"}}, rows)
+	})
+
+	T.Run("Inline", func(T *testing.T) {
+		path := write("inline.yaml", "dataset:\n  - {merchant: m4}\n  - {merchant: m5}\n")
+
+		_, rows, err := script.LoadDataset(path)
+		assert.Nil(T, err)
+		assert.Equal(T, []script.VarMap{{"merchant": "m4"}, {"merchant": "m5"}}, rows)
+	})
+
+	T.Run("None", func(T *testing.T) {
+		path := write("none.yaml", "operations: {}\n")
+
+		ds, rows, err := script.LoadDataset(path)
+		assert.Nil(T, err)
+		assert.Nil(T, ds)
+		assert.Nil(T, rows)
+	})
+
+	T.Run("Subdirectory", func(T *testing.T) {
+		os.Mkdir(filepath.Join(dir, "sub"), 0755)
+		path := write(filepath.Join("sub", "csv.yaml"), "dataset: ../users.csv\n")
+
+		_, rows, err := script.LoadDataset(path)
+		assert.Nil(T, err)
+		assert.Len(T, rows, 2)
+	})
+
+	T.Run("Unsupported", func(T *testing.T) {
+		path := write("xml.yaml", "dataset: users.xml\n")
+
+		_, _, err := script.LoadDataset(path)
+		assert.NotNil(T, err)
+	})
+}
+
+func Test_RowVars(T *testing.T) {
+	vars := script.RowVars(script.VarMap{"host": "localhost"}, script.VarMap{"pin": "111"})
+	assert.Equal(T, script.VarMap{"host": "localhost", "row.pin": "111"}, vars)
+}
+
+func Test_RelativePath(T *testing.T) {
+	assert.Equal(T, filepath.Join("script", "noosa", "users.csv"), script.RelativePath(filepath.Join("script", "noosa", "credit.yaml"), "users.csv"))
+	assert.Equal(T, filepath.Join("spec", "users.csv"), script.RelativePath(filepath.Join("script", "noosa", "credit.yaml"), "../../spec/users.csv"))
+	assert.Equal(T, "/data/users.csv", script.RelativePath(filepath.Join("script", "credit.yaml"), "/data/users.csv"))
+}
@@ -2,11 +2,14 @@ package script
 
 import (
 	"sync"
+	"time"
 
 	gog "github.com/x1n13y84issmd42/gog/graph"
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/strings"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
 )
 
 // ExecutionNode represents a single operation in the script execution graph.
@@ -14,14 +17,25 @@ import (
 // where operation parameters are stored. Those later get loaded into
 // an Operation's own Data() instance.
 type ExecutionNode struct {
-	Operation  contract.Operation
-	OpRefID    string
-	Data       contract.OperationData
-	Mutex      sync.Mutex
-	Result     *contract.OperationResult
-	Use        *OperationDataUse
-	Expect     *OperationDataUse
-	ExpectBody *params.BodyParameters
+	Operation         contract.Operation
+	OpRefID           string
+	SpecID            string
+	Data              contract.OperationData
+	Mutex             sync.Mutex
+	Result            *contract.OperationResult
+	Use               *OperationDataUse
+	Expect            *OperationDataExpect
+	ExpectBody        []expect.Property
+	ExpectBodyMatches interface{}
+	ExpectHeaders     []expect.Header
+	ExpectSnapshot    *expect.SnapshotOptions
+	ExpectMaxTime     time.Duration
+	Until             *Polling
+	ForEach           *Collection
+	Item              *contract.OperationResult
+	Iterations        Iterations
+	If                *Condition
+	Fallback          *contract.OperationData
 }
 
 // NewExecutionNode creates a new ExecutionNode instance.
@@ -29,27 +43,43 @@ func NewExecutionNode(op contract.Operation, opRefID string, opRef *OperationRef
 	n := &ExecutionNode{
 		Operation: op,
 		OpRefID:   opRefID,
+		SpecID:    strings.Split(opRef.OperationID, ".")[0],
+		Item:      &contract.OperationResult{},
 	}
 
 	n.Mutex = sync.Mutex{}
 
-	n.Data.URL = params.URL("", log)
-	n.Data.Query = params.Query(log)
-	n.Data.Headers = params.Headers(log)
-	n.Data.Body = params.Body(log)
+	n.Data = *NewOperationData(log)
 
 	n.Use = &opRef.Use
 	n.Expect = &opRef.Expect
-	n.ExpectBody = params.Body(log)
 
 	return n
 }
 
+// NewOperationData creates a new empty OperationData instance.
+func NewOperationData(log contract.Logger) *contract.OperationData {
+	return &contract.OperationData{
+		URL:     params.URL("", log),
+		Query:   params.Query(log),
+		Headers: params.Headers(log),
+		Body:    params.Body(log),
+	}
+}
+
 // ID returns a uniqe operation node ID.
 func (node *ExecutionNode) ID() gcontract.NodeID {
 	return gcontract.NodeID(node.OpRefID)
 }
 
+// Share makes the node use the results of n2 instead of executing it's operation.
+func (node *ExecutionNode) Share(n2 *ExecutionNode) {
+	node.Result = n2.Result
+	*node.Operation.Result() = *n2.Operation.Result()
+	node.Iterations.Results = n2.Iterations.Results
+	node.Iterations.Collect()
+}
+
 // Lock locks the node to prevent parallel executions.
 func (node *ExecutionNode) Lock() {
 	node.Mutex.Lock()
@@ -1,6 +1,9 @@
 package script
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
 
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
@@ -24,8 +27,8 @@ func NewExecutor(logger contract.Logger, script contract.Script) *Executor {
 	}
 }
 
-// Execute executes.
-func (ex Executor) Execute(graph gcontract.Graph) {
+// Execute executes the graph. It returns true when all the operations have succeeded.
+func (ex Executor) Execute(graph gcontract.Graph) bool {
 	success := true
 	results := make(contract.OperationResults)
 
@@ -44,9 +47,7 @@ func (ex Executor) Execute(graph gcontract.Graph) {
 		}
 	}
 
-	if !success {
-		// os.Exit(255)
-	}
+	return success
 }
 
 // Walk walks the execution graph and executes operations.
@@ -81,37 +82,30 @@ func (ex Executor) Walk(
 		logger := n.Operation.GetLogger()
 		logger.Buffer(true)
 
-		// Setting the request enrichment.
-		n.Operation.Data().Reload()
-		n.Operation.Data().Load(&n.Data)
-		n.Operation.Data().URL.Load(n.Operation.Resolve().Host(""))
+		logger.TestingOperation(n.Operation)
 
-		opSecurity := n.Operation.Resolve().Security("")
-		// ex.Log.NOMESSAGE("security.GetName() = %s", opSecurity.GetName())
+		data := &n.Data
+		skipped := ex.SkippedDependency(graph, n)
 
-		if scriptSec := ex.Script.GetSecurity(opSecurity.GetName()); scriptSec != nil {
-			opSecurity.SetValue(scriptSec.Value)
-			opSecurity.SetToken(scriptSec.Token)
-			opSecurity.SetUsername(scriptSec.Username)
-			opSecurity.SetPassword(scriptSec.Password)
+		if skipped != "" && n.Fallback != nil {
+			logger.UsingFallback(skipped)
+			data = n.Fallback
 		}
 
-		enrichment := []contract.RequestEnrichment{
-			n.Operation.Data().Query,
-			n.Operation.Data().Headers,
-			n.Operation.Data().Body,
-
-			opSecurity,
+		if skipped != "" && n.Fallback == nil {
+			logger.DependencySkipped(skipped)
+			n.Result = ex.Skip(logger)
+		} else if n.If != nil && !n.If.Eval() {
+			logger.ConditionFalse(n.If.Expression)
+			n.Result = ex.Skip(logger)
+		} else if n.ForEach != nil {
+			n.Result = ex.ExecuteForEach(graph, n, data, nresults, logger)
+		} else {
+			n.Result = ex.ExecuteNode(graph, n, data, logger)
+			n.Iterations.Add(n.Result)
 		}
 
-		logger.TestingOperation(n.Operation)
-
-		// Setting the response validation.
-		v := n.Operation.Resolve().Response(n.Expect.Status, "")
-		// v.SetLogger(logger)
-		v.Expect(expect.JSONBody(n.ExpectBody, graph, logger))
-
-		n.Result = test.Operation(n.Operation, &enrichment, v, logger)
+		n.Iterations.Collect()
 		(*nresults)[string(n.ID())] = n.Result
 
 		logger.Flush()
@@ -121,3 +115,132 @@ func (ex Executor) Walk(
 	n.Unlock()
 	nwg.Done()
 }
+
+// ExecuteForEach executes the node once per each item of it's 'forEach' collection.
+// Every iteration is reported separately, the returned result is successful
+// only when all of the iterations are.
+func (ex Executor) ExecuteForEach(
+	graph gcontract.Graph,
+	n *ExecutionNode,
+	data *contract.OperationData,
+	nresults *contract.OperationResults,
+	logger contract.Logger,
+) *contract.OperationResult {
+	result := test.Success()
+
+	items, err := n.ForEach.Items(logger)
+	if err != nil {
+		logger.Error(err)
+		result.Success = false
+		return result
+	}
+
+	for i, item := range items {
+		// Ignoring the error because the item came from a JSON document.
+		n.Item.ResponseBytes, _ = json.Marshal(item)
+		*n.Operation.Result() = contract.OperationResult{
+			Success: true,
+		}
+
+		logger.ForEachItem(int64(i), int64(len(items)))
+
+		itResult := n.Iterations.Add(ex.ExecuteNode(graph, n, data, logger))
+		(*nresults)[fmt.Sprintf("%s[%d]", n.ID(), i)] = itResult
+
+		result.Success = result.Success && itResult.Success
+	}
+
+	return result
+}
+
+// SkippedDependency returns the ID of a skipped node n depends on, if there is any.
+func (ex Executor) SkippedDependency(graph gcontract.Graph, n *ExecutionNode) string {
+	for _an := range graph.AdjacentNodes(n.ID()).Range() {
+		an := _an.(*ExecutionNode)
+		if an.Result != nil && an.Result.Skipped {
+			return string(an.ID())
+		}
+	}
+
+	return ""
+}
+
+// Skip reports the node as skipped and creates a result for it.
+// Skipped nodes are not considered as failed.
+func (ex Executor) Skip(logger contract.Logger) *contract.OperationResult {
+	logger.OperationSkipped()
+
+	return &contract.OperationResult{
+		Success: true,
+		Skipped: true,
+	}
+}
+
+// ExecuteNode sets up the node operation request & response validation,
+// then executes it using the provided data.
+func (ex Executor) ExecuteNode(
+	graph gcontract.Graph,
+	n *ExecutionNode,
+	data *contract.OperationData,
+	logger contract.Logger,
+) *contract.OperationResult {
+	// Setting the request enrichment.
+	// The script data goes last so it can override the spec host.
+	n.Operation.Data().Reload()
+	n.Operation.Data().URL.Load(n.Operation.Resolve().Host(""))
+	n.Operation.Data().Load(data)
+
+	opSecurity := n.Operation.Resolve().Security("")
+	// ex.Log.NOMESSAGE("security.GetName() = %s", opSecurity.GetName())
+
+	if scriptSec := ex.Script.GetSecurity(opSecurity.GetName()); scriptSec != nil {
+		opSecurity.SetValue(scriptSec.Value)
+		opSecurity.SetToken(scriptSec.Token)
+		opSecurity.SetUsername(scriptSec.Username)
+		opSecurity.SetPassword(scriptSec.Password)
+		opSecurity.SetClientID(scriptSec.ClientID)
+		opSecurity.SetClientSecret(scriptSec.ClientSecret)
+	}
+
+	enrichment := []contract.RequestEnrichment{
+		n.Operation.Data().Query,
+		n.Operation.Data().Headers,
+		n.Operation.Data().Body,
+
+		opSecurity,
+		test.NodeID(n.OpRefID),
+	}
+
+	// Setting the response validation.
+	v := n.Operation.Resolve().Response(n.Expect.Status, n.Expect.CT)
+	// v.SetLogger(logger)
+	v.Expect(expect.Headers(n.ExpectHeaders, logger))
+	v.Expect(expect.JSONBody(n.ExpectBody, logger))
+
+	if n.ExpectBodyMatches != nil {
+		v.Expect(expect.BodyMatches(n.ExpectBodyMatches, logger))
+	}
+
+	if n.ExpectSnapshot != nil {
+		opts := *n.ExpectSnapshot
+
+		// Every iteration has it's own snapshot.
+		if n.ForEach != nil {
+			opts.Path = fmt.Sprintf("%s.%d.json", strings.TrimSuffix(opts.Path, ".json"), len(n.Iterations.Results))
+		}
+
+		v.Expect(expect.MatchesSnapshot(opts, logger))
+	}
+
+	if n.ExpectMaxTime > 0 {
+		v.Expect(expect.MaxTime(n.ExpectMaxTime, logger))
+	}
+
+	if n.Until != nil {
+		return test.Validate(n.Until.Poll(n.Operation, func() *contract.OperationResult {
+			return test.Execute(n.Operation, &enrichment, logger)
+		}, logger), v, logger)
+	}
+
+	return test.Operation(n.Operation, &enrichment, v, logger)
+}
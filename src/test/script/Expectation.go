@@ -0,0 +1,413 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+// ExpectationMap is a map of expected values for an OperationRef.
+type ExpectationMap map[string]*ExpectedValue
+
+// ExpectedValue is an expected value of a response property.
+// It's either a value the property must be equal to,
+// or a map of matchers, like {gt: 0, lt: "#getLimit.response.max"}.
+type ExpectedValue struct {
+	Value    string
+	Matchers map[string]interface{}
+}
+
+// UnmarshalYAML accepts both plain values & maps of matchers.
+func (ev *ExpectedValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&ev.Matchers); err == nil {
+		return nil
+	}
+
+	ev.Matchers = nil
+	return unmarshal(&ev.Value)
+}
+
+// Interpolate applies f to all the string values.
+func (ev *ExpectedValue) Interpolate(f func(string) (string, error)) error {
+	v, err := InterpolateValue(ev.Value, f)
+	ev.Value = v.(string)
+
+	if err == nil {
+		_, err = InterpolateValue(ev.Matchers, f)
+	}
+
+	return err
+}
+
+// InterpolateValue applies f to all the string values in v,
+// walking through the nested lists & maps.
+func InterpolateValue(v interface{}, f func(string) (string, error)) (interface{}, error) {
+	var err error
+
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch tv := v.(type) {
+		case string:
+			if err == nil {
+				tv, err = f(tv)
+			}
+
+			return tv
+
+		case []interface{}:
+			for i := range tv {
+				tv[i] = walk(tv[i])
+			}
+
+		case map[interface{}]interface{}:
+			for k := range tv {
+				tv[k] = walk(tv[k])
+			}
+
+		case map[string]interface{}:
+			for k := range tv {
+				tv[k] = walk(tv[k])
+			}
+		}
+
+		return v
+	}
+
+	v = walk(v)
+
+	return v, err
+}
+
+// Operand converts a matcher argument into a string.
+func Operand(v interface{}) (string, error) {
+	switch v.(type) {
+	case string, int, int64, float64, bool:
+		return fmt.Sprint(v), nil
+	}
+
+	return "", errors.Oops(fmt.Sprintf("The matcher argument '%v' should be a single value.", v), nil)
+}
+
+// SetupMatcher creates a matcher for an expected value.
+// Matcher arguments may be templates with references to other operations,
+// in which case edges are added to the graph.
+func (script *Script) SetupMatcher(
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	ev *ExpectedValue,
+) (expect.Matcher, error) {
+	template := func(v interface{}) (contract.ParameterAccess, error) {
+		s, err := Operand(v)
+		if err != nil {
+			return nil, err
+		}
+
+		access, _, err := script.SetupTemplate(graph, opRef, opNode, s)
+		return access, err
+	}
+
+	templates := func(name string, v interface{}, n int) ([]contract.ParameterAccess, error) {
+		list, ok := v.([]interface{})
+		if !ok || (n > 0 && len(list) != n) || len(list) == 0 {
+			return nil, errors.Oops(fmt.Sprintf("The '%s' matcher argument should be a list of %d values.", name, n), nil)
+		}
+
+		res := []contract.ParameterAccess{}
+		for _, item := range list {
+			access, err := template(item)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, access)
+		}
+
+		return res, nil
+	}
+
+	// The "exists: false" kind of matchers are negated.
+	flag := func(v interface{}, m expect.Matcher) (expect.Matcher, error) {
+		s, err := Operand(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if s == "false" {
+			return expect.Not(m), nil
+		}
+
+		return m, nil
+	}
+
+	if ev.Matchers == nil {
+		access, err := template(ev.Value)
+		return expect.Equal(access), err
+	}
+
+	names := []string{}
+	for name := range ev.Matchers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	ms := []expect.Matcher{}
+
+	for _, name := range names {
+		arg := ev.Matchers[name]
+
+		var m expect.Matcher
+		var access contract.ParameterAccess
+		var accesses []contract.ParameterAccess
+		var err error
+
+		switch name {
+		case "exists":
+			m, err = flag(arg, expect.Exists())
+
+		case "absent":
+			m, err = flag(arg, expect.Absent())
+
+		case "isUUID":
+			m, err = flag(arg, expect.IsUUID())
+
+		case "isDate":
+			m, err = flag(arg, expect.IsDate())
+
+		case "type":
+			var t string
+			if t, err = Operand(arg); err == nil {
+				m = expect.Type(t)
+			}
+
+		case "regex":
+			var pattern string
+			if pattern, err = Operand(arg); err == nil {
+				if _, err = regexp.Compile(pattern); err != nil {
+					err = errors.Oops("The regular expression '"+pattern+"' is invalid.", err)
+				} else if access, err = template(arg); err == nil {
+					m = expect.Regex(access)
+				}
+			}
+
+		case "equals":
+			if access, err = template(arg); err == nil {
+				m = expect.Equal(access)
+			}
+
+		case "gt", "gte", "lt", "lte", "contains", "startsWith":
+			if access, err = template(arg); err == nil {
+				m = map[string]func(contract.ParameterAccess) expect.Matcher{
+					"gt":         expect.Greater,
+					"gte":        expect.GreaterOrEqual,
+					"lt":         expect.Less,
+					"lte":        expect.LessOrEqual,
+					"contains":   expect.Contains,
+					"startsWith": expect.StartsWith,
+				}[name](access)
+			}
+
+		case "between":
+			if accesses, err = templates(name, arg, 2); err == nil {
+				m = expect.Between(accesses[0], accesses[1])
+			}
+
+		case "oneOf":
+			if accesses, err = templates(name, arg, 0); err == nil {
+				m = expect.OneOf(accesses...)
+			}
+
+		case "length":
+			nested := &ExpectedValue{}
+			if nested.Matchers, err = MatcherMap(arg); err != nil {
+				nested.Value, err = Operand(arg)
+			}
+
+			if err == nil {
+				if m, err = script.SetupMatcher(graph, opRef, opNode, nested); err == nil {
+					m = expect.Length(m)
+				}
+			}
+
+		default:
+			err = errors.NotFound("Matcher", name, nil)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ms = append(ms, m)
+	}
+
+	if len(ms) == 1 {
+		return ms[0], nil
+	}
+
+	return expect.All(ms...), nil
+}
+
+// MatcherMap converts nested YAML maps into maps of matchers.
+func MatcherMap(v interface{}) (map[string]interface{}, error) {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return tv, nil
+
+	case map[interface{}]interface{}:
+		res := map[string]interface{}{}
+		for k, kv := range tv {
+			res[fmt.Sprint(k)] = kv
+		}
+
+		return res, nil
+	}
+
+	return nil, errors.Oops(fmt.Sprintf("The '%v' value is not a map of matchers.", v), nil)
+}
+
+// IsSelector tells whether an expectation key is a selector,
+// like "$.items[0].id" or ".owner.name", rather than a property name.
+func IsSelector(key string) bool {
+	return strings.HasPrefix(key, "$") || strings.HasPrefix(key, ".") || strings.HasPrefix(key, "[")
+}
+
+// SetupBodyExpectations creates expectations as for the response body properties.
+func (script *Script) SetupBodyExpectations(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	keys := []string{}
+	for key := range opRef.Expect.Body {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		m, err := script.SetupMatcher(graph, opRef, opNode, opRef.Expect.Body[key])
+		if err != nil {
+			return err
+		}
+
+		prop := expect.Property{
+			Key:     key,
+			Matcher: m,
+		}
+
+		if IsSelector(key) {
+			prop.Path, err = params.ParsePath(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		opNode.ExpectBody = append(opNode.ExpectBody, prop)
+	}
+
+	if opRef.Expect.BodyMatches != nil {
+		doc, err := script.SetupDocument(graph, opRef, opNode, opRef.Expect.BodyMatches)
+		if err != nil {
+			return err
+		}
+
+		opNode.ExpectBodyMatches = doc
+	}
+
+	return nil
+}
+
+// SetupHeaderExpectations creates expectations as for the response headers.
+func (script *Script) SetupHeaderExpectations(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	names := []string{}
+	for name := range opRef.Expect.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		m, err := script.SetupMatcher(graph, opRef, opNode, opRef.Expect.Headers[name])
+		if err != nil {
+			return err
+		}
+
+		opNode.ExpectHeaders = append(opNode.ExpectHeaders, expect.Header{
+			Name:    name,
+			Matcher: m,
+		})
+	}
+
+	return nil
+}
+
+// SetupDocument prepares an expected partial document for comparison.
+// The document is either a YAML structure or a string with JSON in it.
+// Its string values are templates, and the numbers are float64,
+// just like the ones in unmarshaled JSON.
+func (script *Script) SetupDocument(
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	v interface{},
+) (interface{}, error) {
+	if s, ok := v.(string); ok {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(s), &doc); err == nil {
+			v = doc
+		}
+	}
+
+	var walk func(v interface{}) (interface{}, error)
+	walk = func(v interface{}) (interface{}, error) {
+		switch tv := v.(type) {
+		case string:
+			access, _, err := script.SetupTemplate(graph, opRef, opNode, tv)
+			return access, err
+
+		case int:
+			return float64(tv), nil
+
+		case int64:
+			return float64(tv), nil
+
+		case uint64:
+			return float64(tv), nil
+
+		case []interface{}:
+			res := []interface{}{}
+			for _, item := range tv {
+				itemV, err := walk(item)
+				if err != nil {
+					return nil, err
+				}
+
+				res = append(res, itemV)
+			}
+
+			return res, nil
+
+		case map[interface{}]interface{}, map[string]interface{}:
+			m, _ := MatcherMap(tv)
+			res := map[string]interface{}{}
+			for k, kv := range m {
+				kvV, err := walk(kv)
+				if err != nil {
+					return nil, err
+				}
+
+				res[k] = kvV
+			}
+
+			return res, nil
+		}
+
+		return v, nil
+	}
+
+	return walk(v)
+}
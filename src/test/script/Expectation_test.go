@@ -0,0 +1,50 @@
+package script_test
+
+import (
+	"testing"
+
+	"github.com/go-yaml/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_ExpectedValue(T *testing.T) {
+	em := script.ExpectationMap{}
+	err := yaml.Unmarshal([]byte(`
+id: 42
+name: {startsWith: "${prefix}", length: {gt: 0}}
+`), &em)
+
+	assert.Nil(T, err)
+	assert.Equal(T, "42", em["id"].Value)
+	assert.Nil(T, em["id"].Matchers)
+	assert.Equal(T, "${prefix}", em["name"].Matchers["startsWith"])
+
+	err = em["name"].Interpolate(script.Vars{{"prefix": "pet"}}.Interpolate)
+	assert.Nil(T, err)
+	assert.Equal(T, "pet", em["name"].Matchers["startsWith"])
+
+	length, err := script.MatcherMap(em["name"].Matchers["length"])
+	assert.Nil(T, err)
+	assert.Equal(T, 0, length["gt"])
+}
+
+func Test_IsSelector(T *testing.T) {
+	assert.False(T, script.IsSelector("id"))
+	assert.False(T, script.IsSelector("x-id"))
+	assert.True(T, script.IsSelector("$[0].id"))
+	assert.True(T, script.IsSelector(".owner.name"))
+	assert.True(T, script.IsSelector("[*].id"))
+}
+
+func Test_OperationDataSnapshot(T *testing.T) {
+	expect := script.OperationDataExpect{}
+	err := yaml.Unmarshal([]byte(`snapshot: true`), &expect)
+	assert.Nil(T, err)
+	assert.Equal(T, &script.OperationDataSnapshot{Enabled: true}, expect.Snapshot)
+
+	expect = script.OperationDataExpect{}
+	err = yaml.Unmarshal([]byte(`snapshot: {name: pet, ignore: [.id]}`), &expect)
+	assert.Nil(T, err)
+	assert.Equal(T, &script.OperationDataSnapshot{Enabled: true, Name: "pet", Ignore: []string{".id"}}, expect.Snapshot)
+}
@@ -0,0 +1,108 @@
+package script
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// Collection is a set of values in a response of some operation,
+// selected with a non-singular selector like "[*]" or ".pets[?(@.status=='available')]".
+// 'forEach' operations are executed once per each of it's items.
+type Collection struct {
+	OpID   string
+	Result *contract.OperationResult
+	Path   *params.Path
+}
+
+// NewCollection creates a new Collection instance from a selector
+// which may select multiple values.
+func NewCollection(opID string, result *contract.OperationResult, selector string) (*Collection, error) {
+	path, err := params.ParsePath(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.Singular() {
+		return nil, errors.Oops("The 'forEach' reference of '"+opID+"' should select multiple values, like '#"+opID+".response[*]'.", nil)
+	}
+
+	return &Collection{
+		OpID:   opID,
+		Result: result,
+		Path:   path,
+	}, nil
+}
+
+// Items returns the collection items.
+func (col *Collection) Items(log contract.Logger) ([]interface{}, error) {
+	var data interface{}
+
+	err := json.Unmarshal(col.Result.ResponseBytes, &data)
+	if err != nil {
+		return nil, errors.Oops("The response of '"+col.OpID+"' is not a valid JSON.", err)
+	}
+
+	return col.Path.Select(data), nil
+}
+
+// Iterations keeps results of all the executions of an operation,
+// so they can be referenced individually or all at once.
+// Referenced iterations are collected into dedicated OperationResult instances
+// which are created in advance, at the execution graph building time.
+type Iterations struct {
+	Results []*contract.OperationResult
+	Refs    map[string]*contract.OperationResult
+}
+
+// Ref returns an OperationResult instance to hold
+// the iteration "N" or all the iterations "*".
+func (its *Iterations) Ref(iteration string) *contract.OperationResult {
+	if its.Refs == nil {
+		its.Refs = map[string]*contract.OperationResult{}
+	}
+
+	if its.Refs[iteration] == nil {
+		its.Refs[iteration] = &contract.OperationResult{}
+	}
+
+	return its.Refs[iteration]
+}
+
+// Add stores a copy of the result of an iteration.
+func (its *Iterations) Add(result *contract.OperationResult) *contract.OperationResult {
+	res := *result
+	its.Results = append(its.Results, &res)
+	return &res
+}
+
+// Collect fills the referenced iteration results.
+// All the iterations are represented by a JSON array of their responses.
+func (its *Iterations) Collect() {
+	for iteration, ref := range its.Refs {
+		if iteration == "*" {
+			responses := []json.RawMessage{}
+			for _, res := range its.Results {
+				if json.Valid(res.ResponseBytes) {
+					responses = append(responses, res.ResponseBytes)
+				} else {
+					resp, _ := json.Marshal(string(res.ResponseBytes))
+					responses = append(responses, resp)
+				}
+			}
+
+			ref.Success = true
+			ref.ResponseBytes, _ = json.Marshal(responses)
+			continue
+		}
+
+		// Ignoring the error because regexp in SplitIteration requires an integer.
+		i, _ := strconv.Atoi(iteration)
+		if i < len(its.Results) {
+			*ref = *its.Results[i]
+		}
+	}
+}
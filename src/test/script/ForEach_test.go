@@ -0,0 +1,76 @@
+package script_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_Collection(T *testing.T) {
+	log := log.New("plain", 0)
+
+	T.Run("Items", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte(`{"items": [{"id": 1}, {"id": 2}]}`),
+		}
+
+		col, err := script.NewCollection("listPets", result, ".items[*]")
+		assert.Nil(T, err)
+
+		items, err := col.Items(log)
+		assert.Nil(T, err)
+		assert.Len(T, items, 2)
+	})
+
+	T.Run("Filter", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte(`[{"id": 1, "status": "sold"}, {"id": 2, "status": "available"}]`),
+		}
+
+		col, err := script.NewCollection("listPets", result, "[?(@.status=='available')].id")
+		assert.Nil(T, err)
+
+		items, err := col.Items(log)
+		assert.Nil(T, err)
+		assert.Equal(T, []interface{}{float64(2)}, items)
+	})
+
+	T.Run("Singular", func(T *testing.T) {
+		_, err := script.NewCollection("listPets", &contract.OperationResult{}, ".items")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Syntax error", func(T *testing.T) {
+		_, err := script.NewCollection("listPets", &contract.OperationResult{}, ".items[*")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Not a JSON", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte(`items`),
+		}
+
+		col, _ := script.NewCollection("listPets", result, ".items[*]")
+		_, err := col.Items(log)
+		assert.NotNil(T, err)
+	})
+}
+
+func Test_Iterations(T *testing.T) {
+	its := script.Iterations{}
+	first := its.Ref("0")
+	all := its.Ref("*")
+	missing := its.Ref("5")
+
+	its.Add(&contract.OperationResult{Success: true, ResponseBytes: []byte(`{"id":1}`)})
+	its.Add(&contract.OperationResult{Success: false, ResponseBytes: []byte(`oops`)})
+	its.Collect()
+
+	assert.Equal(T, `{"id":1}`, string(first.ResponseBytes))
+	assert.Equal(T, `[{"id":1},"oops"]`, string(all.ResponseBytes))
+	assert.Nil(T, missing.ResponseBytes)
+	assert.Equal(T, first, its.Ref("0"))
+}
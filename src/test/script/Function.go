@@ -3,21 +3,93 @@ package script
 import (
 	"regexp"
 
+	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/strings"
 )
 
 // Dereference checks if v is a reference to another operation
-// and returns a ParameterAccess function for it.
-func Dereference(v string) (bool, string, string) {
-	rx := regexp.MustCompile("#(?P<opRef>\\w+)\\.response(?P<selector>.*)")
+// and returns the referenced operation name, request or response part
+// (see params.SplitPart) and the selector within that part.
+// The referenced operation name may contain an iteration index
+// of a 'forEach' operation, like "#getPet[1].response.name" or "#getPet[*].response".
+func Dereference(v string) (bool, string, string, string) {
+	rx := regexp.MustCompile("#(?P<opRef>\\w+(\\[(\\d+|\\*)\\])?)\\.(?P<kind>response|request)(?P<selector>.*)")
 
 	if rx.Match([]byte(v)) {
 		matches := strings.RxMatches(v, rx)
+		part, selector := params.SplitPart(matches["kind"], matches["selector"])
 
 		//TODO: pass-through dereferencing, anyone?
-		return true, matches["opRef"], matches["selector"]
+		return true, matches["opRef"], part, selector
 	}
 
-	return false, "", ""
+	return false, "", "", ""
+
+}
+
+// DereferenceItem checks if v is a reference to the current item
+// of a 'forEach' operation, like "#item.id", and returns it's selector.
+func DereferenceItem(v string) (bool, string) {
+	rx := regexp.MustCompile("^#item(?P<selector>([\\.\\[].*)?)$")
+
+	if rx.Match([]byte(v)) {
+		matches := strings.RxMatches(v, rx)
+		return true, matches["selector"]
+	}
+
+	return false, ""
+}
+
+// SplitIteration splits the iteration index off the referenced operation name.
+// "getPet[1]" becomes ("getPet", "1"), "getPet" becomes ("getPet", "").
+func SplitIteration(opRefID string) (string, string) {
+	rx := regexp.MustCompile("^(?P<opRef>\\w+)\\[(?P<iteration>\\d+|\\*)\\]$")
+
+	if rx.Match([]byte(opRefID)) {
+		matches := strings.RxMatches(opRefID, rx)
+		return matches["opRef"], matches["iteration"]
+	}
+
+	return opRefID, ""
+}
+
+// DereferenceStatus checks if v is a reference to the HTTP status code
+// of an operation response, like "#createTX.status", and returns the operation name.
+// It is a shorthand for "#createTX.response.status".
+func DereferenceStatus(v string) (bool, string) {
+	rx := regexp.MustCompile("^#(?P<opRef>\\w+(\\[\\d+\\])?)\\.status$")
 
+	if rx.Match([]byte(v)) {
+		matches := strings.RxMatches(v, rx)
+		return true, matches["opRef"]
+	}
+
+	return false, ""
+}
+
+// ShadowedProperty checks if v is a reference to a response part, like "#getPet.response.status",
+// while the response body has a property with the same name among properties.
+// Such references used to point at the body property, so it returns the property name
+// and the reference which points at the property now, like "#getPet.response.body.status".
+func ShadowedProperty(v string, properties []string) (bool, string, string) {
+	rx := regexp.MustCompile("^(?P<prefix>#\\w+(\\[(\\d+|\\*)\\])?\\.response)\\.(?P<part>[a-z]+)(?P<selector>([\\.\\[].*)?)$")
+
+	if !rx.Match([]byte(v)) {
+		return false, "", ""
+	}
+
+	matches := strings.RxMatches(v, rx)
+	part, _ := params.SplitPart("response", "."+matches["part"]+matches["selector"])
+
+	if part != "response."+matches["part"] {
+		return false, "", ""
+	}
+
+	for _, property := range properties {
+		if property == matches["part"] {
+			return true, property, matches["prefix"] + ".body." + property + matches["selector"]
+		}
+	}
+
+	return false, "", ""
 }
@@ -0,0 +1,110 @@
+package script_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_Dereference(T *testing.T) {
+	T.Run("Plain", func(T *testing.T) {
+		isref, opRefID, part, selector := script.Dereference("#listPets.response[0].id")
+		assert.True(T, isref)
+		assert.Equal(T, "listPets", opRefID)
+		assert.Equal(T, params.PartResponseBody, part)
+		assert.Equal(T, "[0].id", selector)
+	})
+
+	T.Run("Parts", func(T *testing.T) {
+		_, _, part, selector := script.Dereference("#createPet.response.headers[Location]")
+		assert.Equal(T, params.PartResponseHeaders, part)
+		assert.Equal(T, "[Location]", selector)
+
+		_, _, part, selector = script.Dereference("#createPet.response.body.status")
+		assert.Equal(T, params.PartResponseBody, part)
+		assert.Equal(T, ".status", selector)
+
+		_, _, part, selector = script.Dereference("#createPet.request.query.limit")
+		assert.Equal(T, params.PartRequestQuery, part)
+		assert.Equal(T, ".limit", selector)
+	})
+
+	T.Run("Iteration", func(T *testing.T) {
+		isref, opRefID, _, selector := script.Dereference("#getPet[1].response.name")
+		assert.True(T, isref)
+		assert.Equal(T, "getPet[1]", opRefID)
+		assert.Equal(T, ".name", selector)
+
+		opRefID, iteration := script.SplitIteration(opRefID)
+		assert.Equal(T, "getPet", opRefID)
+		assert.Equal(T, "1", iteration)
+	})
+
+	T.Run("All iterations", func(T *testing.T) {
+		_, opRefID, _, _ := script.Dereference("#getPet[*].response")
+		opRefID, iteration := script.SplitIteration(opRefID)
+		assert.Equal(T, "getPet", opRefID)
+		assert.Equal(T, "*", iteration)
+	})
+
+	T.Run("Item", func(T *testing.T) {
+		isitem, selector := script.DereferenceItem("#item.id")
+		assert.True(T, isitem)
+		assert.Equal(T, ".id", selector)
+
+		isitem, selector = script.DereferenceItem("#item")
+		assert.True(T, isitem)
+		assert.Equal(T, "", selector)
+
+		isitem, _ = script.DereferenceItem("#items.id")
+		assert.False(T, isitem)
+	})
+}
+
+func Test_DereferenceStatus(T *testing.T) {
+	T.Run("Status", func(T *testing.T) {
+		isstatus, opRefID := script.DereferenceStatus("#createTX.status")
+		assert.True(T, isstatus)
+		assert.Equal(T, "createTX", opRefID)
+	})
+
+	T.Run("Response field", func(T *testing.T) {
+		isstatus, _ := script.DereferenceStatus("#createTX.response.status")
+		assert.False(T, isstatus)
+	})
+}
+
+func Test_ShadowedProperty(T *testing.T) {
+	properties := []string{"id", "status", "headers"}
+
+	T.Run("Status", func(T *testing.T) {
+		shadowed, property, bodyRef := script.ShadowedProperty("#getPet.response.status", properties)
+		assert.True(T, shadowed)
+		assert.Equal(T, "status", property)
+		assert.Equal(T, "#getPet.response.body.status", bodyRef)
+	})
+
+	T.Run("Headers", func(T *testing.T) {
+		shadowed, _, bodyRef := script.ShadowedProperty("#getPet[1].response.headers[Location]", properties)
+		assert.True(T, shadowed)
+		assert.Equal(T, "#getPet[1].response.body.headers[Location]", bodyRef)
+	})
+
+	T.Run("Not shadowed", func(T *testing.T) {
+		for _, ref := range []string{
+			"#getPet.response.body.status",
+			"#getPet.response.cookies[sid]",
+			"#getPet.response.id",
+			"#getPet.request.query.status",
+			"#getPet.status",
+		} {
+			shadowed, _, _ := script.ShadowedProperty(ref, properties)
+			assert.False(T, shadowed, ref)
+		}
+
+		shadowed, _, _ := script.ShadowedProperty("#getPet.response.status", []string{"id"})
+		assert.False(T, shadowed)
+	})
+}
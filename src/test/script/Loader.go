@@ -10,32 +10,99 @@ import (
 	"github.com/x1n13y84issmd42/oasis/src/utility"
 )
 
-// Load loads a script file.
-func Load(path string, log contract.Logger) contract.Script {
-	fileData, fileErr := ioutil.ReadFile(path)
-	if fileErr != nil {
-		return NoScript(fileErr, log)
+// Load loads a script file. The "${VAR}" expressions in script values
+// are replaced with the vars values first, then with the script's own variables,
+// the OS environment variables and the variables from the .env file
+// in the current working directory.
+func Load(path string, vars VarMap, log contract.Logger) contract.Script {
+	scripts, err := LoadAll(path, []VarMap{vars}, log)
+	if err != nil {
+		return NoScript(err, log)
 	}
 
-	script := &Script{
-		EntityTrait: contract.Entity(log),
-		Sec:         make(map[string]*contract.SecurityAccess),
+	return scripts[0]
+}
+
+// LoadAll loads a script file once for every set of vars, like for every dataset row.
+// The file, the .env file and the specs are read once and shared by the scripts.
+func LoadAll(path string, varSets []VarMap, log contract.Logger) ([]*Script, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dotenv, err := LoadDotEnv(".env")
+	if err != nil {
+		return nil, err
 	}
 
-	yaml.Unmarshal([]byte(fileData), script)
+	// Variables may change the spec paths, so the specs are kept by path.
+	loaded := make(map[string]contract.Spec)
+	scripts := []*Script{}
 
-	specs := make(map[string]contract.OperationAccess)
+	for _, vars := range varSets {
+		script := &Script{
+			EntityTrait: contract.Entity(log),
+			Sec:         make(map[string]*contract.SecurityAccess),
+			Path:        path,
+		}
+
+		yaml.Unmarshal([]byte(fileData), script)
+
+		err = script.Interpolate(vars, EnvVars(), dotenv)
+		if err != nil {
+			return nil, err
+		}
+
+		script.Specs = make(map[string]contract.Spec)
+
+		for k, v := range script.SpecPaths {
+			specPath, _ := filepath.Abs(filepath.Join("script", v))
+			if loaded[specPath] == nil {
+				loaded[specPath] = utility.Load(specPath, script.Log)
+			}
+
+			script.Specs[k] = loaded[specPath]
+		}
+
+		script.OperationCache = script.NewOperationCache()
 
-	for k, v := range script.SpecPaths {
-		specPath, _ := filepath.Abs(filepath.Join("script", v))
-		spec := utility.Load(specPath, script.Log)
+		//TODO: some validation is required
+		// Like unique node IDs.
+
+		scripts = append(scripts, script)
+	}
+
+	return scripts, nil
+}
+
+// NewOperationCache creates an operation cache for the script specs.
+func (script *Script) NewOperationCache() api.OperationCache {
+	specs := make(map[string]contract.OperationAccess)
+	for k, spec := range script.Specs {
 		specs[k] = spec
 	}
 
-	script.OperationCache = api.NewOperationCache(specs)
+	return api.NewOperationCache(specs)
+}
+
+// Fresh returns a copy of the script with new operations, so the copy can be executed
+// without sharing the operation results, like in concurrent load test runs.
+// The script data is shared, as the execution doesn't change it.
+func (script *Script) Fresh() *Script {
+	fresh := *script
+	fresh.Sec = make(map[string]*contract.SecurityAccess)
+	fresh.OperationCache = script.NewOperationCache()
 
-	//TODO: some validation is required
-	// Like unique node IDs.
+	return &fresh
+}
+
+// RelativePath resolves the path found in the script file at scriptPath.
+// Relative dataset paths are relative to the script file.
+func RelativePath(scriptPath string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
 
-	return script
+	return filepath.Join(filepath.Dir(scriptPath), path)
 }
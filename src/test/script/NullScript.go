@@ -25,6 +25,12 @@ func (s *NullScript) GetExecutionGraph() gcontract.Graph {
 	return nil
 }
 
+// GetSpecs reports an error.
+func (s *NullScript) GetSpecs() map[string]contract.Spec {
+	s.Report()
+	return nil
+}
+
 // GetSecurity reports an error.
 func (s *NullScript) GetSecurity(name string) *contract.SecurityAccess {
 	s.Report()
@@ -0,0 +1,140 @@
+package script
+
+import (
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// OperationDataUntil corresponds to the 'until' block of the OperationRef in a script file.
+// It can be either a condition string or a map with the condition
+// and polling settings.
+type OperationDataUntil struct {
+	Condition   string  `yaml:"condition"`
+	Interval    string  `yaml:"interval"`
+	Backoff     float64 `yaml:"backoff"`
+	MaxAttempts int64   `yaml:"maxAttempts"`
+	Timeout     string  `yaml:"timeout"`
+}
+
+// UnmarshalYAML allows the 'until' block to be a single condition string.
+func (until *OperationDataUntil) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&until.Condition); err == nil {
+		return nil
+	}
+
+	type plain OperationDataUntil
+	return unmarshal((*plain)(until))
+}
+
+// PollingClock tells the time and waits between the polling attempts.
+type PollingClock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// RealPollingClock is the PollingClock of the time package.
+type RealPollingClock struct{}
+
+// Now calls time.Now.
+func (RealPollingClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep calls time.Sleep.
+func (RealPollingClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// Polling settings for operations which need to be repeated
+// until some condition on their response holds.
+type Polling struct {
+	Condition   *Condition
+	Interval    time.Duration
+	Backoff     float64
+	MaxAttempts int64
+	Timeout     time.Duration
+
+	// Clock is RealPollingClock when not set.
+	Clock PollingClock
+}
+
+// NewPolling creates a new Polling instance from the script data.
+// When neither maxAttempts nor timeout is specified, the operation
+// is attempted at most 10 times.
+func NewPolling(until *OperationDataUntil, cond *Condition) (*Polling, error) {
+	polling := &Polling{
+		Condition:   cond,
+		Interval:    time.Second,
+		Backoff:     1,
+		MaxAttempts: until.MaxAttempts,
+	}
+
+	var err error
+
+	if until.Interval != "" {
+		polling.Interval, err = time.ParseDuration(until.Interval)
+		if err != nil {
+			return nil, errors.Oops("Cannot parse the 'interval' value '"+until.Interval+"'.", err)
+		}
+	}
+
+	if until.Timeout != "" {
+		polling.Timeout, err = time.ParseDuration(until.Timeout)
+		if err != nil {
+			return nil, errors.Oops("Cannot parse the 'timeout' value '"+until.Timeout+"'.", err)
+		}
+	}
+
+	if until.Backoff > 0 {
+		polling.Backoff = until.Backoff
+	}
+
+	if polling.MaxAttempts == 0 && polling.Timeout == 0 {
+		polling.MaxAttempts = 10
+	}
+
+	return polling, nil
+}
+
+// Poll executes the operation repeatedly until the condition holds
+// or the attempts or time run out. Only the last attempt is validated.
+func (polling *Polling) Poll(
+	op contract.Operation,
+	execute func() *contract.OperationResult,
+	log contract.Logger,
+) *contract.OperationResult {
+	clock := polling.Clock
+	if clock == nil {
+		clock = RealPollingClock{}
+	}
+
+	start := clock.Now()
+	delay := polling.Interval
+
+	for attempt := int64(1); ; attempt++ {
+		// The result instance is kept because other nodes reference it.
+		*op.Result() = contract.OperationResult{
+			Success: true,
+		}
+
+		result := execute()
+
+		if result.HTTPResponse != nil && polling.Condition.Eval() {
+			return result
+		}
+
+		if (polling.MaxAttempts > 0 && attempt >= polling.MaxAttempts) ||
+			(polling.Timeout > 0 && clock.Now().Sub(start)+delay > polling.Timeout) {
+			log.PollingGaveUp(polling.Condition.Expression, attempt)
+			result.Success = false
+			return result
+		}
+
+		log.Polling(polling.Condition.Expression, attempt, delay)
+
+		clock.Sleep(delay)
+		delay = time.Duration(float64(delay) * polling.Backoff)
+	}
+}
@@ -0,0 +1,125 @@
+package script_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+// pollingClock is a PollingClock which only pretends to sleep.
+type pollingClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (clock *pollingClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *pollingClock) Sleep(d time.Duration) {
+	clock.slept = append(clock.slept, d)
+	clock.now = clock.now.Add(d)
+}
+
+func Test_Polling(T *testing.T) {
+	// poll polls an operation which is done on the doneAt attempt, 0 means never.
+	poll := func(polling *script.Polling, doneAt int) (contract.Operation, *contract.OperationResult, int, *pollingClock) {
+		op := api.NewOperationPrototype(log.NewPlain(0))
+		clock := &pollingClock{now: time.Now()}
+		polling.Clock = clock
+
+		ref := params.Reference{
+			OpID:     "getStatus",
+			Result:   op.Result(),
+			Part:     params.PartResponseBody,
+			Selector: ".status",
+			Log:      log.NewPlain(0),
+		}
+
+		polling.Condition = &script.Condition{
+			Expression: "#self.response.body.status == done",
+			Left: func() string {
+				v, _ := ref.Lookup()
+				return v
+			},
+			Operator: "==",
+			Right:    params.Value("done"),
+			Refs:     []params.Reference{ref},
+		}
+
+		attempts := 0
+		result := polling.Poll(op, func() *contract.OperationResult {
+			attempts++
+
+			status := "pending"
+			if attempts == doneAt {
+				status = "done"
+			}
+
+			res := op.Result()
+			res.HTTPResponse = &http.Response{StatusCode: 200, Header: http.Header{}}
+			res.ResponseBytes = []byte(fmt.Sprintf(`{"status": "%s", "attempt": %d}`, status, attempts))
+
+			if attempts == 1 {
+				res.HTTPResponse.Header.Set("X-First", "yes")
+			}
+
+			return res
+		}, log.NewPlain(0))
+
+		return op, result, attempts, clock
+	}
+
+	T.Run("Condition holds", func(T *testing.T) {
+		_, result, attempts, clock := poll(&script.Polling{Interval: time.Second, Backoff: 1, MaxAttempts: 10}, 3)
+
+		assert.Equal(T, 3, attempts)
+		assert.True(T, result.Success)
+		assert.Equal(T, []time.Duration{time.Second, time.Second}, clock.slept)
+	})
+
+	T.Run("Max attempts", func(T *testing.T) {
+		_, result, attempts, clock := poll(&script.Polling{Interval: time.Second, Backoff: 1, MaxAttempts: 4}, 0)
+
+		assert.Equal(T, 4, attempts)
+		assert.False(T, result.Success)
+		assert.Len(T, clock.slept, 3)
+	})
+
+	T.Run("Timeout", func(T *testing.T) {
+		// The attempts happen at 0s, 1s and 3s, the next one would be past the timeout at 7s.
+		_, result, attempts, clock := poll(&script.Polling{Interval: time.Second, Backoff: 2, Timeout: 5 * time.Second}, 0)
+
+		assert.Equal(T, 3, attempts)
+		assert.False(T, result.Success)
+		assert.Equal(T, []time.Duration{time.Second, 2 * time.Second}, clock.slept)
+	})
+
+	T.Run("Backoff", func(T *testing.T) {
+		_, _, _, clock := poll(&script.Polling{Interval: 100 * time.Millisecond, Backoff: 1.5, MaxAttempts: 4}, 0)
+
+		assert.Equal(T, []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 225 * time.Millisecond}, clock.slept)
+	})
+
+	T.Run("Last attempt", func(T *testing.T) {
+		op, result, _, _ := poll(&script.Polling{Interval: time.Second, Backoff: 1, MaxAttempts: 10}, 2)
+
+		// Downstream references hold the operation result instance.
+		assert.Same(T, op.Result(), result)
+
+		attempt := params.Reference{OpID: "getStatus", Result: op.Result(), Part: params.PartResponseBody, Selector: ".attempt", Log: log.NewPlain(0)}
+		assert.Equal(T, "2", attempt.Value()())
+
+		header := params.Reference{OpID: "getStatus", Result: op.Result(), Part: params.PartResponseHeaders, Selector: "[X-First]", Log: log.NewPlain(0)}
+		_, ok := header.Lookup()
+		assert.False(T, ok)
+	})
+}
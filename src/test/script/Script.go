@@ -1,6 +1,9 @@
 package script
 
 import (
+	gostrings "strings"
+	"time"
+
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
@@ -11,10 +14,14 @@ import (
 // OperationRef is a node of execution graph as desfined in the script file.
 // It references a spec operation and contains the needed data.
 type OperationRef struct {
-	OperationID string           `yaml:"operationId"`
-	After       string           `yaml:"after"`
-	Use         OperationDataUse `yaml:"use"`
-	Expect      OperationDataUse `yaml:"expect"`
+	OperationID string              `yaml:"operationId"`
+	After       string              `yaml:"after"`
+	Use         OperationDataUse    `yaml:"use"`
+	Expect      OperationDataExpect `yaml:"expect"`
+	Until       *OperationDataUntil `yaml:"until"`
+	ForEach     string              `yaml:"forEach"`
+	If          string              `yaml:"if"`
+	Fallback    *OperationDataUse   `yaml:"fallback"`
 }
 
 // OperationDataMap is a map of parameters for an OperationRef.
@@ -54,10 +61,13 @@ type OperationDataUse struct {
 
 // OperationDataExpect corresponds to the 'expect' block of the OperationRef in a script file.
 type OperationDataExpect struct {
-	Body    OperationDataMap `yaml:"body"`
-	Headers OperationDataMap `yaml:"headers"`
-	CT      string           `yaml:"CT"`
-	Status  int64            `yaml:"status"`
+	Body        ExpectationMap         `yaml:"body"`
+	BodyMatches interface{}            `yaml:"bodyMatches"`
+	Headers     ExpectationMap         `yaml:"headers"`
+	CT          string                 `yaml:"CT"`
+	Status      int64                  `yaml:"status"`
+	MaxTime     string                 `yaml:"maxTime"`
+	Snapshot    *OperationDataSnapshot `yaml:"snapshot"`
 }
 
 // Script is a complex API testing scenario.
@@ -66,11 +76,14 @@ type OperationDataExpect struct {
 type Script struct {
 	api.OperationCache
 	contract.EntityTrait
+	Vars       VarMap                              `yaml:"vars"`
 	SpecPaths  map[string]string                   `yaml:"specs"`
 	Securities map[string]*contract.ScriptSecurity `yaml:"security"`
 	Operations map[string]*OperationRef            `yaml:"operations"`
 
-	Sec map[string]*contract.SecurityAccess `yaml:-`
+	Sec   map[string]*contract.SecurityAccess `yaml:"-"`
+	Specs map[string]contract.Spec            `yaml:"-"`
+	Path  string                              `yaml:"-"`
 }
 
 // GetExecutionGraph builds and returns an operation execution graph.
@@ -108,7 +121,42 @@ func (script *Script) GetExecutionGraph() gcontract.Graph {
 			return NoGraph(err, script.Log)
 		}
 
-		err = script.SetupDataDependency(graph, &opRef.Expect.Body, opNode.ExpectBody, opNode, opRef, opRefID)
+		err = script.SetupBodyExpectations(graph, opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupHeaderExpectations(graph, opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupExpectations(opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupSnapshot(opRef, opNode, opRefID)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupPolling(graph, opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupForEach(graph, opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupIf(graph, opRef, opNode)
+		if err != nil {
+			return NoGraph(err, script.Log)
+		}
+
+		err = script.SetupFallback(graph, opRef, opNode, opRefID)
 		if err != nil {
 			return NoGraph(err, script.Log)
 		}
@@ -156,28 +204,170 @@ func (script *Script) SetupDependency(
 	return op2, nil
 }
 
-// SetupSecurityDependency adds an edge to the execution graph if opRef has an 'after' specified.
-func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
-	refdep := func(p *contract.ParameterAccess, v string) error {
-		isref, op2RefID, selector := Dereference(v)
-		if isref {
-			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode)
+// SetupReference adds an edge to the execution graph between opNode and the node
+// referenced by op2RefID and returns the referenced operation result.
+// When op2RefID contains an iteration index, like "getPet[1]" or "getPet[*]",
+// the result of the corresponding iteration(s) is returned.
+func (script *Script) SetupReference(
+	op2RefID string,
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+) (contract.Operation, *contract.OperationResult, error) {
+	op2RefID, iteration := SplitIteration(op2RefID)
+
+	op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if iteration == "" {
+		return op2, op2.Result(), nil
+	}
+
+	opNode2 := script.GetNode(graph, op2RefID, op2, script.Operations[op2RefID])
 
+	return op2, opNode2.Iterations.Ref(iteration), nil
+}
+
+// SetupItemReference checks that opRef is a 'forEach' one
+// and returns the current item holder of opNode.
+func (script *Script) SetupItemReference(opRef *OperationRef, opNode *ExecutionNode) (*contract.OperationResult, error) {
+	if opRef.ForEach == "" {
+		return nil, errors.Oops("The '#item' references are available only in 'forEach' operations, '"+opNode.OpRefID+"' is not one.", nil)
+	}
+
+	return opNode.Item, nil
+}
+
+// NewReference creates a reference to a part of the result of some operation.
+// Reference selectors are validated here, so their errors are reported
+// when a script is loaded rather than when it is executed.
+func (script *Script) NewReference(opID string, result *contract.OperationResult, part string, selector string) (params.Reference, error) {
+	ref := params.Reference{
+		OpID:     opID,
+		Result:   result,
+		Part:     part,
+		Selector: selector,
+		Log:      script.Log,
+	}
+
+	return ref, ref.Validate()
+}
+
+// SetupTemplate parses a parameter value template and creates an access function for it.
+// References in the template may point to results of other operations,
+// in which case edges are added to the graph, to the node's own result as "#self.response...",
+// to the current 'forEach' item as "#item..." or to HTTP status codes as "#op.status".
+// It also returns a description of the value source.
+func (script *Script) SetupTemplate(
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	v string,
+) (contract.ParameterAccess, string, error) {
+	return script.setupTemplate(graph, opRef, opNode, v, nil)
+}
+
+// setupTemplate is SetupTemplate which, when refs is not nil, collects the template references
+// and makes them look their values up instead of reporting the missing ones.
+func (script *Script) setupTemplate(
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	v string,
+	refs *[]params.Reference,
+) (contract.ParameterAccess, string, error) {
+	access := func(r params.Reference) contract.ParameterAccess {
+		if refs == nil {
+			return r.Value()
+		}
+
+		*refs = append(*refs, r)
+
+		return func() string {
+			v, _ := r.Lookup()
+			return v
+		}
+	}
+
+	tpl, err := params.ParseTemplate(v)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sources := []string{}
+	addSource := func(source string) {
+		for _, s := range sources {
+			if s == source {
+				return
+			}
+		}
+
+		sources = append(sources, source)
+	}
+
+	resolve := func(ref string) (contract.ParameterAccess, bool, error) {
+		if isitem, selector := DereferenceItem(ref); isitem {
+			item, err := script.SetupItemReference(opRef, opNode)
 			if err != nil {
-				return err
+				return nil, false, err
+			}
+
+			addSource(opRef.ForEach + " item")
+			r, err := script.NewReference(opRef.ForEach+" item", item, params.PartResponseBody, selector)
+			return access(r), true, err
+		}
+
+		isref, op2RefID, part, selector := Dereference(ref)
+
+		if isstatus, statusOpRefID := DereferenceStatus(ref); isstatus {
+			isref, op2RefID, part, selector = true, statusOpRefID, params.PartResponseStatus, ""
+		}
+
+		if !isref {
+			return nil, false, nil
+		}
+
+		op2 := opNode.Operation
+		result := op2.Result()
+		if op2RefID != "self" {
+			op2, result, err = script.SetupReference(op2RefID, graph, opRef, opNode)
+			if err != nil {
+				return nil, false, err
 			}
-			// script.Log.NOMESSAGE("SetSecDep op '%s' depends on '%s' via security.", opNode.ID(), op2RefID)
-
-			(*p) = (params.Reference{
-				OpID:     op2.ID(),
-				Result:   op2.Result(),
-				Selector: selector,
-				Log:      script.Log,
-			}).Value()
-		} else {
-			(*p) = params.Value(v)
 		}
 
+		if shadowed, property, bodyRef := ShadowedProperty(ref, op2.Resolve().ResponseProperties()); shadowed {
+			script.Log.ShadowedProperty(ref, property, bodyRef)
+		}
+
+		addSource(op2.ID() + " node")
+		r, err := script.NewReference(op2.ID(), result, part, selector)
+		return access(r), true, err
+	}
+
+	compiled, err := tpl.Compile(resolve, script.Log)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(sources) == 0 {
+		sources = append(sources, "script template")
+	}
+
+	return compiled, gostrings.Join(sources, ", "), nil
+}
+
+// SetupSecurityDependency adds an edge to the execution graph if opRef has an 'after' specified.
+func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	refdep := func(p *contract.ParameterAccess, v string) error {
+		access, _, err := script.SetupTemplate(graph, opRef, opNode, v)
+		if err != nil {
+			return err
+		}
+
+		(*p) = access
 		return nil
 	}
 
@@ -211,6 +401,16 @@ func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *Oper
 				return err
 			}
 
+			err = refdep(&script.Sec[secName].ClientID, sec.ClientID)
+			if err != nil {
+				return err
+			}
+
+			err = refdep(&script.Sec[secName].ClientSecret, sec.ClientSecret)
+			if err != nil {
+				return err
+			}
+
 			// script.Log.NOMESSAGE("SetSecDep.script.Sec[secName]: %#v", script.Sec[secName])
 		}
 
@@ -219,6 +419,137 @@ func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *Oper
 	return nil
 }
 
+// SetupExpectations parses the non-parameter expectations of opRef,
+// such as the maximum response time.
+func (script *Script) SetupExpectations(opRef *OperationRef, opNode *ExecutionNode) error {
+	if opRef.Expect.MaxTime != "" {
+		maxTime, err := time.ParseDuration(opRef.Expect.MaxTime)
+		if err != nil {
+			return errors.Oops("Cannot parse the 'maxTime' expectation value '"+opRef.Expect.MaxTime+"'.", err)
+		}
+
+		opNode.ExpectMaxTime = maxTime
+	}
+
+	return nil
+}
+
+// SetupCondition parses a condition expression and creates access functions
+// for it's operands. Operands may reference the node's own result as "#self.response...",
+// the current 'forEach' item as "#item..." or results of other operations,
+// in which case edges are added to the graph. The "#op.status" operands
+// reference the HTTP status codes of operation responses.
+func (script *Script) SetupCondition(
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	expr string,
+) (*Condition, error) {
+	left, operator, right, err := ParseCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := &Condition{
+		Expression: expr,
+		Operator:   operator,
+	}
+
+	operand := func(v string) (contract.ParameterAccess, error) {
+		access, _, err := script.setupTemplate(graph, opRef, opNode, v, &cond.Refs)
+		return access, err
+	}
+
+	if cond.Left, err = operand(left); err != nil {
+		return nil, err
+	}
+
+	if cond.Right, err = operand(right); err != nil {
+		return nil, err
+	}
+
+	return cond, nil
+}
+
+// SetupIf parses the 'if' condition of opRef. Operations are skipped
+// when their conditions don't hold.
+func (script *Script) SetupIf(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	if opRef.If == "" {
+		return nil
+	}
+
+	var err error
+	opNode.If, err = script.SetupCondition(graph, opRef, opNode, opRef.If)
+	return err
+}
+
+// SetupFallback sets up the fallback parameters of opRef, which are used instead
+// of the 'use' ones when some of the operations opRef depends on were skipped.
+func (script *Script) SetupFallback(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	if opRef.Fallback == nil {
+		return nil
+	}
+
+	opNode.Fallback = NewOperationData(script.Log)
+
+	fallbacks := []struct {
+		src *OperationDataMap
+		dst contract.Set
+	}{
+		{&opRef.Fallback.Path, opNode.Fallback.URL},
+		{&opRef.Fallback.Query, opNode.Fallback.Query},
+		{&opRef.Fallback.Headers, opNode.Fallback.Headers},
+		{&opRef.Fallback.Body, opNode.Fallback.Body},
+	}
+
+	for _, fb := range fallbacks {
+		err := script.SetupDataDependency(graph, fb.src, fb.dst, opNode, opRef, opRefID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetupPolling parses the 'until' condition of opRef. Operands of the condition
+// may reference the node's own result as "#self.response..." or results
+// of other operations, in which case edges are added to the graph.
+func (script *Script) SetupPolling(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	if opRef.Until == nil {
+		return nil
+	}
+
+	cond, err := script.SetupCondition(graph, opRef, opNode, opRef.Until.Condition)
+	if err != nil {
+		return err
+	}
+
+	opNode.Until, err = NewPolling(opRef.Until, cond)
+	return err
+}
+
+// SetupForEach parses the 'forEach' reference of opRef,
+// which makes the operation to be executed once per each item of the referenced array.
+func (script *Script) SetupForEach(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
+	if opRef.ForEach == "" {
+		return nil
+	}
+
+	isref, op2RefID, part, selector := Dereference(opRef.ForEach)
+	if !isref || part != params.PartResponseBody {
+		return errors.Oops("The 'forEach' value '"+opRef.ForEach+"' is not a reference to a response body.", nil)
+	}
+
+	_, result, err := script.SetupReference(op2RefID, graph, opRef, opNode)
+	if err != nil {
+		return err
+	}
+
+	opNode.ForEach, err = NewCollection(op2RefID, result, selector)
+	return err
+}
+
 // SetupAfterDependency adds an edge to the execution graph if opRef has an 'after' specified.
 func (script *Script) SetupAfterDependency(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
 	if opRef.After != "" {
@@ -239,25 +570,30 @@ func (script *Script) SetupDataDependency(
 	opRef *OperationRef,
 	opRefID string,
 ) error {
-	refParams := params.NewReferenceSource(script.Log)
+	accessParams := params.NewAccessSource()
 	memParams := params.NewMemorySource("script data")
 
 	for pn, pv := range *srcParams {
-		isref, op2RefID, selector := Dereference(pv)
-		if isref {
-			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode)
-			if err != nil {
-				return err
-			}
+		tpl, err := params.ParseTemplate(pv)
+		if err != nil {
+			return err
+		}
 
-			// Adding the value so it's available for op later.
-			refParams.AddReference(pn, op2.ID()+" node", op2.Result(), selector)
-		} else {
-			memParams.Add(pn, pv)
+		if tpl.Literal() {
+			memParams.Add(pn, tpl.Text())
+			continue
 		}
+
+		access, source, err := script.SetupTemplate(graph, opRef, opNode, pv)
+		if err != nil {
+			return err
+		}
+
+		// Adding the value so it's available for op later.
+		accessParams.Add(pn, access, source)
 	}
 
-	dstParams.Load(refParams)
+	dstParams.Load(accessParams)
 	dstParams.Load(memParams)
 
 	return nil
@@ -279,6 +615,11 @@ func (script *Script) GetNode(graph gcontract.Graph, opRefID string, op contract
 	return opNode
 }
 
+// GetSpecs returns the script's specs by their IDs.
+func (script *Script) GetSpecs() map[string]contract.Spec {
+	return script.Specs
+}
+
 // GetSecurity returns the script's security parameters.
 func (script *Script) GetSecurity(name string) *contract.SecurityAccess {
 	return script.Sec[name]
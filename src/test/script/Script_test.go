@@ -0,0 +1,113 @@
+package script_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_Script(T *testing.T) {
+	dir, _ := ioutil.TempDir("", "oasis")
+	defer os.RemoveAll(dir)
+
+	// Spec paths are relative to the script directory of the working directory.
+	scriptDir, _ := filepath.Abs("script")
+	specPath, _ := filepath.Abs("../../../spec/test/dollars.yaml")
+	specPath, _ = filepath.Rel(scriptDir, specPath)
+
+	path := filepath.Join(dir, "dollars.yaml")
+	ioutil.WriteFile(path, []byte(`
+specs:
+  dollars: `+specPath+`
+security:
+  script: {username: user, password: pa$$word}
+operations:
+  getPrices:
+    operationId: dollars.getPrices
+    use:
+      headers: {X-Price: $$5, X-Total: $$$upper(five)}
+  getWallet:
+    operationId: dollars.getWallet
+`), 0644)
+
+	s := script.Load(path, script.VarMap{}, log.NewPlain(0))
+	graph := s.GetExecutionGraph()
+
+	T.Run("Literal $$", func(T *testing.T) {
+		req, _ := http.NewRequest("GET", "http://localhost/v1/prices", nil)
+		graph.Node(gcontract.NodeID("getPrices")).(*script.ExecutionNode).Data.Headers.Enrich(req, log.NewPlain(0))
+
+		assert.Equal(T, "$$5", req.Header.Get("X-Price"))
+		assert.Equal(T, "$FIVE", req.Header.Get("X-Total"))
+	})
+
+	T.Run("Script security $$", func(T *testing.T) {
+		assert.Equal(T, "pa$$word", s.GetSecurity("script").Password())
+	})
+
+	T.Run("Spec security $$", func(T *testing.T) {
+		req, _ := http.NewRequest("GET", "http://localhost/v1/prices", nil)
+		s.GetSpecs()["dollars"].GetOperation("getPrices").Resolve().Security("").Enrich(req, log.NewPlain(0))
+
+		username, password, _ := req.BasicAuth()
+		assert.Equal(T, "user", username)
+		assert.Equal(T, "pa$$word", password)
+	})
+}
+
+func Test_LoadAll(T *testing.T) {
+	dir, _ := ioutil.TempDir("", "oasis")
+	defer os.RemoveAll(dir)
+
+	scriptDir, _ := filepath.Abs("script")
+	specPath, _ := filepath.Abs("../../../spec/test/dollars.yaml")
+	specPath, _ = filepath.Rel(scriptDir, specPath)
+
+	path := filepath.Join(dir, "rows.yaml")
+	ioutil.WriteFile(path, []byte(`
+specs:
+  dollars: `+specPath+`
+operations:
+  getPrices:
+    operationId: dollars.getPrices
+    use:
+      headers: {X-Currency: "${row.currency}"}
+`), 0644)
+
+	scripts, err := script.LoadAll(path, []script.VarMap{{"row.currency": "EUR"}, {"row.currency": "USD"}}, log.NewPlain(0))
+	assert.Nil(T, err)
+	assert.Len(T, scripts, 2)
+
+	T.Run("Shared specs", func(T *testing.T) {
+		assert.Same(T, scripts[0].Specs["dollars"], scripts[1].Specs["dollars"])
+	})
+
+	T.Run("Vars", func(T *testing.T) {
+		for i, currency := range []string{"EUR", "USD"} {
+			req, _ := http.NewRequest("GET", "http://localhost/v1/prices", nil)
+			scripts[i].GetExecutionGraph().Node(gcontract.NodeID("getPrices")).(*script.ExecutionNode).Data.Headers.Enrich(req, log.NewPlain(0))
+
+			assert.Equal(T, currency, req.Header.Get("X-Currency"))
+		}
+	})
+
+	T.Run("Fresh", func(T *testing.T) {
+		a := scripts[0].Fresh()
+		b := scripts[0].Fresh()
+
+		assert.NotSame(T, a.GetOperation("dollars.getPrices"), b.GetOperation("dollars.getPrices"))
+		assert.Same(T, a.GetOperation("dollars.getPrices"), a.GetOperation("dollars.getPrices"))
+	})
+
+	T.Run("Error", func(T *testing.T) {
+		_, err := script.LoadAll(filepath.Join(dir, "none.yaml"), []script.VarMap{{}}, log.NewPlain(0))
+		assert.NotNil(T, err)
+	})
+}
@@ -0,0 +1,69 @@
+package script
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+// OperationDataSnapshot corresponds to the 'snapshot' field of the 'expect' block
+// of the OperationRef in a script file. It can be either a boolean
+// or a map with the snapshot settings.
+type OperationDataSnapshot struct {
+	Enabled bool
+	Name    string   `yaml:"name"`
+	Headers []string `yaml:"headers"`
+	Ignore  []string `yaml:"ignore"`
+}
+
+// UnmarshalYAML allows the 'snapshot' field to be a single boolean.
+func (snap *OperationDataSnapshot) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&snap.Enabled); err == nil {
+		return nil
+	}
+
+	type plain OperationDataSnapshot
+	snap.Enabled = true
+	return unmarshal((*plain)(snap))
+}
+
+// SnapshotDir returns the directory where the script snapshots are kept.
+// It's the "__snapshots__/SCRIPT_NAME" directory next to the script file.
+func (script *Script) SnapshotDir() string {
+	name := strings.TrimSuffix(filepath.Base(script.Path), filepath.Ext(script.Path))
+	return filepath.Join(filepath.Dir(script.Path), "__snapshots__", name)
+}
+
+// SetupSnapshot creates the snapshot options for the node.
+// The snapshots are named after the operations, unless a name is given.
+func (script *Script) SetupSnapshot(opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	snap := opRef.Expect.Snapshot
+	if snap == nil || !snap.Enabled {
+		return nil
+	}
+
+	name := snap.Name
+	if name == "" {
+		name = opRefID
+	}
+
+	opts := &expect.SnapshotOptions{
+		Path:    filepath.Join(script.SnapshotDir(), name+".json"),
+		Headers: snap.Headers,
+	}
+
+	for _, selector := range snap.Ignore {
+		path, err := params.ParsePath(selector)
+		if err != nil {
+			return err
+		}
+
+		opts.Ignore = append(opts.Ignore, path)
+	}
+
+	opNode.ExpectSnapshot = opts
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package script
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	gostrings "strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// VarMap is a map of variable values.
+type VarMap map[string]string
+
+// Vars is a list of variable sources used to interpolate "${VAR}"
+// expressions in script values. Sources are looked up in order,
+// so the first ones take precedence.
+type Vars []VarMap
+
+// Get returns a variable value from the first source which has it.
+func (vars Vars) Get(name string) (string, bool) {
+	for _, src := range vars {
+		if v, ok := src[name]; ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// Interpolate replaces the "${VAR}" expressions in v with variable values.
+// Undefined variables are errors. "$${VAR}" is a literal "${VAR}".
+func (vars Vars) Interpolate(v string) (string, error) {
+	rx := regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+	var err error
+
+	res := rx.ReplaceAllStringFunc(v, func(expr string) string {
+		if gostrings.HasPrefix(expr, "$$") {
+			return expr[1:]
+		}
+
+		name := gostrings.TrimSpace(expr[2 : len(expr)-1])
+		value, ok := vars.Get(name)
+		if !ok && err == nil {
+			err = errors.Oops("The variable '"+name+"' used in '"+v+"' is not defined.", nil)
+		}
+
+		return value
+	})
+
+	return res, err
+}
+
+// EnvVars returns the OS environment variables.
+func EnvVars() VarMap {
+	vars := VarMap{}
+
+	for _, kv := range os.Environ() {
+		kvs := gostrings.SplitN(kv, "=", 2)
+		vars[kvs[0]] = kvs[1]
+	}
+
+	return vars
+}
+
+// LoadDotEnv reads variables from a .env file.
+// It's lines are like "KEY=value" or "export KEY='value'",
+// empty lines & lines starting with '#' are ignored.
+// A missing file is not an error and produces no variables.
+func LoadDotEnv(path string) (VarMap, error) {
+	vars := VarMap{}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return vars, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := gostrings.TrimSpace(scanner.Text())
+		if line == "" || gostrings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = gostrings.TrimPrefix(line, "export ")
+		kvs := gostrings.SplitN(line, "=", 2)
+		if len(kvs) != 2 {
+			return nil, errors.Oops("Cannot parse the line '"+line+"' in "+path+".", nil)
+		}
+
+		vars[gostrings.TrimSpace(kvs[0])] = Unquote(gostrings.TrimSpace(kvs[1]))
+	}
+
+	return vars, scanner.Err()
+}
+
+// Interpolate replaces the "${VAR}" expressions in all the script values.
+// The script's own variables may use the other sources, and take
+// precedence over them, except for the CLI-supplied ones.
+func (script *Script) Interpolate(cli VarMap, env VarMap, dotenv VarMap) error {
+	ownVars := VarMap{}
+	for vn, vv := range script.Vars {
+		v, err := Vars{cli, env, dotenv}.Interpolate(vv)
+		if err != nil {
+			return err
+		}
+
+		ownVars[vn] = v
+	}
+
+	vars := Vars{cli, ownVars, env, dotenv}
+
+	var err error
+
+	str := func(v *string) {
+		if err == nil {
+			*v, err = vars.Interpolate(*v)
+		}
+	}
+
+	dataMap := func(m OperationDataMap) {
+		for pn, pv := range m {
+			str(&pv)
+			m[pn] = pv
+		}
+	}
+
+	use := func(use *OperationDataUse) {
+		dataMap(use.Path)
+		dataMap(use.Query)
+		dataMap(use.Headers)
+		dataMap(use.Body)
+		str(&use.Security)
+		str(&use.CT)
+	}
+
+	for sn, sp := range script.SpecPaths {
+		str(&sp)
+		script.SpecPaths[sn] = sp
+	}
+
+	for _, sec := range script.Securities {
+		str(&sec.Value)
+		str(&sec.Token)
+		str(&sec.Username)
+		str(&sec.Password)
+		str(&sec.ClientID)
+		str(&sec.ClientSecret)
+	}
+
+	for _, opRef := range script.Operations {
+		str(&opRef.OperationID)
+		str(&opRef.After)
+		str(&opRef.ForEach)
+		str(&opRef.If)
+
+		use(&opRef.Use)
+
+		if opRef.Fallback != nil {
+			use(opRef.Fallback)
+		}
+
+		for _, ev := range opRef.Expect.Body {
+			if err == nil {
+				err = ev.Interpolate(vars.Interpolate)
+			}
+		}
+
+		for _, ev := range opRef.Expect.Headers {
+			if err == nil {
+				err = ev.Interpolate(vars.Interpolate)
+			}
+		}
+
+		if err == nil {
+			opRef.Expect.BodyMatches, err = InterpolateValue(opRef.Expect.BodyMatches, vars.Interpolate)
+		}
+
+		str(&opRef.Expect.CT)
+		str(&opRef.Expect.MaxTime)
+
+		if snap := opRef.Expect.Snapshot; snap != nil {
+			str(&snap.Name)
+			for i := range snap.Headers {
+				str(&snap.Headers[i])
+			}
+
+			for i := range snap.Ignore {
+				str(&snap.Ignore[i])
+			}
+		}
+
+		if opRef.Until != nil {
+			str(&opRef.Until.Condition)
+			str(&opRef.Until.Interval)
+			str(&opRef.Until.Timeout)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,84 @@
+package script_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+func Test_Vars(T *testing.T) {
+	vars := script.Vars{
+		script.VarMap{"host": "cli.host"},
+		script.VarMap{"host": "script.host", "pin": "111"},
+	}
+
+	T.Run("Precedence", func(T *testing.T) {
+		v, err := vars.Interpolate("https://${host}/v1")
+		assert.Nil(T, err)
+		assert.Equal(T, "https://cli.host/v1", v)
+	})
+
+	T.Run("Multiple", func(T *testing.T) {
+		v, err := vars.Interpolate("${ host }:${pin}")
+		assert.Nil(T, err)
+		assert.Equal(T, "cli.host:111", v)
+	})
+
+	T.Run("Escaped", func(T *testing.T) {
+		v, err := vars.Interpolate("$${pin}")
+		assert.Nil(T, err)
+		assert.Equal(T, "${pin}", v)
+	})
+
+	T.Run("Undefined", func(T *testing.T) {
+		_, err := vars.Interpolate("${password}")
+		assert.NotNil(T, err)
+	})
+}
+
+func Test_LoadDotEnv(T *testing.T) {
+	T.Run("Missing", func(T *testing.T) {
+		vars, err := script.LoadDotEnv("/nonexistent/.env")
+		assert.Nil(T, err)
+		assert.Empty(T, vars)
+	})
+
+	T.Run("Values", func(T *testing.T) {
+		dir, _ := ioutil.TempDir("", "oasis")
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, ".env")
+		ioutil.WriteFile(path, []byte("# Comment\nHOST=localhost\n\nexport PASSWORD='p=ss'\n"), 0644)
+
+		vars, err := script.LoadDotEnv(path)
+		assert.Nil(T, err)
+		assert.Equal(T, script.VarMap{"HOST": "localhost", "PASSWORD": "p=ss"}, vars)
+	})
+}
+
+func Test_ScriptInterpolate(T *testing.T) {
+	s := &script.Script{
+		Vars: script.VarMap{
+			"host": "${API_HOST}/v2",
+		},
+		Operations: map[string]*script.OperationRef{
+			"getPet": {
+				Use: script.OperationDataUse{
+					Path: script.OperationDataMap{
+						"HOSTNAME": "${host}",
+						"petId":    "${pet}",
+					},
+				},
+			},
+		},
+	}
+
+	err := s.Interpolate(script.VarMap{"pet": "42"}, script.VarMap{"API_HOST": "http://localhost"}, script.VarMap{})
+	assert.Nil(T, err)
+	assert.Equal(T, "http://localhost/v2", s.Operations["getPet"].Use.Path["HOSTNAME"])
+	assert.Equal(T, "42", s.Operations["getPet"].Use.Path["petId"])
+}